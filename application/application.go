@@ -12,12 +12,16 @@ import (
 	"syscall"
 	"time"
 
+	"my-pointlings-be/internal/auth"
+	"my-pointlings-be/internal/database"
 	"my-pointlings-be/internal/handler"
+	"my-pointlings-be/internal/middleware"
+	"my-pointlings-be/internal/models"
 	"my-pointlings-be/internal/repository"
+	"my-pointlings-be/internal/repository/memory"
 	"my-pointlings-be/internal/service"
 
 	"github.com/gin-gonic/gin"
-	_ "github.com/jackc/pgx/v5/stdlib"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
@@ -25,13 +29,35 @@ import (
 func Run() {
 	cfg := config.Load()
 
-	db := setupDB(cfg)
-
-	pointlingRepo := repository.New(db)
+	pointlingRepo, closeRepo := setupRepository(cfg)
 	pointlingService := service.New(pointlingRepo)
 	pointlingHandler := handler.New(pointlingService)
-	router := setupRouter()
-	setupPointlingRouter(router, pointlingHandler)
+	verifier, err := auth.NewVerifier(auth.Config{
+		HS256Secret: cfg.JWTSecret,
+		JWKSFile:    cfg.JWKSFile,
+		Issuer:      cfg.JWTIssuer,
+		Audience:    cfg.JWTAudience,
+		UserIDClaim: cfg.JWTUserIDClaim,
+		RolesClaim:  cfg.JWTRolesClaim,
+	})
+	if err != nil {
+		log.Fatalf("failed to set up authentication: %v", err)
+	}
+	adminKeys, err := auth.ParseAPIKeys(cfg.AdminAPIKeys)
+	if err != nil {
+		log.Fatalf("failed to load admin api keys: %v", err)
+	}
+
+	router := setupRouter(cfg.RequestTimeout)
+	setupPointlingRouter(router, pointlingHandler,
+		middleware.Authenticate(verifier),
+		middleware.RequireAdmin(verifier, adminKeys),
+		middleware.Idempotency(pointlingRepo, cfg.IdempotencyTTL),
+	)
+
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	defer stopPurge()
+	go purgeIdempotencyKeys(purgeCtx, pointlingRepo)
 
 	srv := &http.Server{
 		Addr:    cfg.HTTPAddr,
@@ -57,34 +83,69 @@ func Run() {
 	defer cancel()
 
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatalf("server shutdown failed: %v", err)
+		log.Printf("server shutdown failed: %v", err)
 	}
 
+	// In-flight requests are done; stop background work before the pool goes.
+	stopPurge()
+	closeRepo()
+
 	log.Println("server stopped gracefully")
 }
 
-func setupDB(cfg *config.Config) *sql.DB {
-	// Connect to Supabase Postgres via pgx driver
-	db, err := sql.Open("pgx", cfg.DBAddr)
-	if err != nil {
-		log.Fatalf("failed to connect to database: %v", err)
+// setupRepository builds the configured repository backend. The returned
+// func releases its resources once the server has drained.
+func setupRepository(cfg *config.Config) (repository.API, func()) {
+	if cfg.Repository == config.RepositoryMemory {
+		log.Println("using the in-memory repository; data is lost on restart")
+		repo := memory.New()
+		repo.SeedPersonalities(models.StarterPersonalities()...)
+		return repo, func() {}
 	}
-	defer db.Close()
 
-	// Verify connection
-	if err := db.Ping(); err != nil {
-		log.Fatalf("failed to ping database: %v", err)
+	db := setupDB(cfg)
+	if cfg.AutoMigrate {
+		applied, err := database.MigrateUp(context.Background(), db)
+		if err != nil {
+			log.Fatalf("failed to migrate database: %v", err)
+		}
+		for _, m := range applied {
+			log.Printf("applied migration %04d_%s", m.Version, m.Name)
+		}
+	}
+	return repository.New(db), func() {
+		if err := db.Close(); err != nil {
+			log.Printf("closing database pool: %v", err)
+		}
 	}
+}
 
+// setupDB opens the connection pool, retrying while the database comes up.
+// Run closes it once the server has drained.
+func setupDB(cfg *config.Config) *sql.DB {
+	db, err := database.Open(context.Background(), database.Config{
+		URL:              cfg.DBAddr,
+		MaxOpenConns:     cfg.DBMaxOpenConns,
+		MaxIdleConns:     cfg.DBMaxIdleConns,
+		ConnMaxLifetime:  cfg.DBConnMaxLifetime,
+		ConnMaxIdleTime:  cfg.DBConnMaxIdleTime,
+		StatementTimeout: cfg.DBStatementTimeout,
+		ConnectTimeout:   cfg.DBConnectTimeout,
+	})
+	if err != nil {
+		log.Fatalf("failed to connect to database: %v", err)
+	}
 	return db
 }
 
-func setupRouter() *gin.Engine {
+func setupRouter(requestTimeout time.Duration) *gin.Engine {
 	r := gin.Default()
 
 	// Middleware
 	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
+	r.Use(middleware.Errors())
+	r.Use(middleware.Timeout(requestTimeout))
 
 	// Swagger endpoint
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -99,36 +160,97 @@ func setupRouter() *gin.Engine {
 	return r
 }
 
+// purgeIdempotencyKeys periodically deletes expired idempotency keys until
+// ctx is cancelled. Expired keys are also ignored on lookup, so this only
+// keeps the table small.
+func purgeIdempotencyKeys(ctx context.Context, repo repository.API) {
+	ticker := time.NewTicker(models.IdempotencyPurgeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := repo.PurgeExpiredIdempotencyKeys(ctx); err != nil {
+				log.Printf("purge idempotency keys: %v", err)
+			} else if n > 0 {
+				log.Printf("purged %d expired idempotency keys", n)
+			}
+		}
+	}
+}
+
 func setupPointlingRouter(
 	r *gin.Engine,
-	pointlingHandler handler.API) {
+	pointlingHandler handler.API,
+	authenticate gin.HandlerFunc,
+	requireAdmin gin.HandlerFunc,
+	idempotency gin.HandlerFunc) {
 
+	// Authentication runs first so idempotency keys are scoped to the caller.
 	api := r.Group("/api")
+	api.Use(authenticate, idempotency)
 	{
 		// User endpoints
-		api.GET("/users", pointlingHandler.ListUsers)
 		api.POST("/users", pointlingHandler.CreateUser)
 		api.GET("/users/:user_id", pointlingHandler.GetUser)
-		api.PATCH("/users/:user_id/points", pointlingHandler.UpdateUserPoints)
+		api.GET("/users/:user_id/points/ledger", pointlingHandler.GetLedger)
+		api.GET("/users/:user_id/points/reconcile", pointlingHandler.ReconcilePoints)
+		api.PATCH("/users/:user_id/timezone", pointlingHandler.UpdateUserTimezone)
 
 		// Pointlings endpoints
 		api.POST("/pointlings", pointlingHandler.CreatePointling)
 		api.GET("/pointlings/:pointling_id", pointlingHandler.GetPointling)
+		api.GET("/pointlings/:pointling_id/render", pointlingHandler.GetRenderManifest)
 		api.POST("/pointlings/:pointling_id/xp", pointlingHandler.AddXP)
+		api.GET("/pointlings/:pointling_id/xp/history", pointlingHandler.GetXPHistory)
+		api.GET("/pointlings/:pointling_id/xp/limits", pointlingHandler.GetXPLimits)
 		api.PATCH("/pointlings/:pointling_id/nickname", pointlingHandler.UpdateNickname)
 		api.GET("/pointlings/user/:user_id", pointlingHandler.ListUserPointlings)
+		api.GET("/personalities", pointlingHandler.ListPersonalities)
+
+		// Level reward endpoints
+		api.GET("/pointlings/:pointling_id/rewards", pointlingHandler.GetPendingRewards)
+		api.POST("/pointlings/:pointling_id/rewards", pointlingHandler.ClaimReward)
+
+		// Pointling color endpoints
+		api.GET("/pointlings/:pointling_id/colors", pointlingHandler.ListColors)
+		api.PATCH("/pointlings/:pointling_id/color", pointlingHandler.SetColor)
 
 		// Items endpoints
 		api.GET("/items", pointlingHandler.ListItems)
 		api.GET("/items/:item_id", pointlingHandler.GetItem)
-		api.POST("/items", pointlingHandler.CreateItem)
 
 		// Pointling inventory endpoints
 		api.GET("/pointlings/:pointling_id/items", pointlingHandler.GetInventory)
-		api.POST("/pointlings/:pointling_id/items/:item_id", pointlingHandler.AcquireItem)
+		api.POST("/pointlings/:pointling_id/items/:item_id/purchase", pointlingHandler.PurchaseItem)
 		api.PATCH("/pointlings/:pointling_id/items/:item_id/equip", pointlingHandler.ToggleEquipped)
 
-		// Points spending
-		api.POST("/users/:user_id/points/spend", pointlingHandler.SpendPoints)
+		// Points history
+		api.GET("/users/:user_id/points/history", pointlingHandler.GetSpendHistory)
+	}
+
+	admin := r.Group("/admin")
+	admin.Use(requireAdmin, idempotency)
+	{
+		// User lookup and balance adjustments
+		admin.GET("/users", pointlingHandler.ListUsers)
+		admin.GET("/users/:user_id", pointlingHandler.GetUser)
+		admin.PATCH("/users/:user_id/points", pointlingHandler.UpdateUserPoints)
+
+		// Item catalog
+		admin.POST("/items", pointlingHandler.CreateItem)
+
+		// XP config administration
+		admin.GET("/xp-config", pointlingHandler.GetXPConfig)
+		admin.GET("/xp-config/versions", pointlingHandler.ListXPConfigVersions)
+		admin.PUT("/xp-config", pointlingHandler.UpdateXPConfig)
+
+		// Points ledger maintenance
+		admin.POST("/ledger/backfill", pointlingHandler.BackfillLedger)
+		admin.POST("/spends/:spend_id/refund", pointlingHandler.RefundSpend)
+
+		// Audit trail of admin changes
+		admin.GET("/audit", pointlingHandler.ListAuditLog)
 	}
 }
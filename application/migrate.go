@@ -0,0 +1,73 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"my-pointlings-be/internal/database"
+	"my-pointlings-be/pkg/config"
+)
+
+const migrateUsage = "usage: api migrate up | down [steps] | status"
+
+// Migrate runs the migrate subcommand: up applies pending migrations, down
+// rolls back the newest one (or steps of them) and status lists them all.
+func Migrate(args []string) {
+	if len(args) == 0 || (args[0] != "up" && args[0] != "down" && args[0] != "status") {
+		log.Fatal(migrateUsage)
+	}
+	cfg := config.Load()
+	if cfg.Repository != config.RepositoryPostgres {
+		log.Fatalf("migrate needs REPOSITORY=%s; the %s repository has no schema", config.RepositoryPostgres, cfg.Repository)
+	}
+	db := setupDB(cfg)
+	defer db.Close()
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := database.MigrateUp(ctx, db)
+		for _, m := range applied {
+			fmt.Printf("applied  %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatalf("migrate up: %v", err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("schema is up to date")
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				log.Fatalf("invalid steps %q: %s", args[1], migrateUsage)
+			}
+			steps = n
+		}
+		reverted, err := database.MigrateDown(ctx, db, steps)
+		for _, m := range reverted {
+			fmt.Printf("reverted %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatalf("migrate down: %v", err)
+		}
+		if len(reverted) == 0 {
+			fmt.Println("no migrations to revert")
+		}
+	case "status":
+		statuses, err := database.MigrationStatuses(ctx, db)
+		if err != nil {
+			log.Fatalf("migrate status: %v", err)
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.AppliedAt != nil {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05Z07:00")
+			}
+			fmt.Printf("%04d_%-20s %s\n", s.Version, s.Name, state)
+		}
+	}
+}
@@ -1,9 +1,15 @@
 package main
 
 import (
+	"os"
+
 	"my-pointlings-be/application"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		application.Migrate(os.Args[2:])
+		return
+	}
 	application.Run()
 }
@@ -1,30 +1,98 @@
--- WARNING: This schema is for context only and is not meant to be run.
--- Table order and constraints may not be valid for execution.
+-- Reference snapshot of the schema, for context only.
+-- The schema is created by the migrations in internal/database/migrations;
+-- run `api migrate up` to bootstrap a database.
 
+CREATE TYPE item_category AS ENUM ('ACCESSORY', 'FEATURE');
+CREATE TYPE item_slot AS ENUM ('HAT', 'SHOES', 'FACE', 'WINGS');
+CREATE TYPE item_rarity AS ENUM ('COMMON', 'RARE', 'EPIC', 'LEGENDARY');
+
+CREATE TABLE public.admin_audit_log (
+  audit_id bigint NOT NULL DEFAULT nextval('admin_audit_log_audit_id_seq'::regclass),
+  actor text NOT NULL,
+  action text NOT NULL,
+  target_type text NOT NULL,
+  target_id text NOT NULL,
+  before jsonb,
+  after jsonb,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT admin_audit_log_pkey PRIMARY KEY (audit_id)
+);
+CREATE TABLE public.idempotency_keys (
+  idem_key text NOT NULL,
+  request_hash text NOT NULL,
+  status_code integer,
+  content_type text,
+  response_body bytea,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  expires_at timestamp with time zone NOT NULL,
+  CONSTRAINT idempotency_keys_pkey PRIMARY KEY (idem_key)
+);
 CREATE TABLE public.items (
   item_id bigint NOT NULL DEFAULT nextval('items_item_id_seq'::regclass),
-  item_category USER-DEFINED NOT NULL,
-  slot USER-DEFINED,
+  category item_category NOT NULL,
+  slot item_slot,
   asset_id text NOT NULL,
   name text NOT NULL,
-  rarity USER-DEFINED NOT NULL,
+  rarity item_rarity NOT NULL,
   price_points integer,
   unlock_level integer,
   CONSTRAINT items_pkey PRIMARY KEY (item_id)
 );
+CREATE TABLE public.level_rewards (
+  reward_id bigint NOT NULL DEFAULT nextval('level_rewards_reward_id_seq'::regclass),
+  pointling_id bigint NOT NULL,
+  level integer NOT NULL,
+  options jsonb NOT NULL,
+  chosen_option jsonb,
+  offered_at timestamp with time zone NOT NULL DEFAULT now(),
+  expires_at timestamp with time zone NOT NULL,
+  claimed_at timestamp with time zone,
+  CONSTRAINT level_rewards_pkey PRIMARY KEY (reward_id),
+  CONSTRAINT level_rewards_pointling_level_key UNIQUE (pointling_id, level),
+  CONSTRAINT level_rewards_pointling_id_fkey FOREIGN KEY (pointling_id) REFERENCES public.pointlings(pointling_id)
+);
+CREATE TABLE public.personalities (
+  personality_id integer NOT NULL DEFAULT nextval('personalities_personality_id_seq'::regclass),
+  code text NOT NULL UNIQUE,
+  name text NOT NULL,
+  traits jsonb NOT NULL DEFAULT '[]'::jsonb,
+  idle_animation_ids jsonb NOT NULL DEFAULT '[]'::jsonb,
+  dialogue_tags jsonb NOT NULL DEFAULT '[]'::jsonb,
+  weight integer NOT NULL DEFAULT 1 CHECK (weight >= 0),
+  xp_bonus_percent jsonb NOT NULL DEFAULT '{}'::jsonb,
+  CONSTRAINT personalities_pkey PRIMARY KEY (personality_id)
+);
+-- Starter rows (playful, thrifty, loyal, sleepy) come from migration
+-- 0004_seed_personalities.
+CREATE TABLE public.point_ledger (
+  entry_id bigint NOT NULL DEFAULT nextval('point_ledger_entry_id_seq'::regclass),
+  user_id bigint NOT NULL,
+  delta bigint NOT NULL CHECK (delta <> 0),
+  balance_after bigint NOT NULL CHECK (balance_after >= 0),
+  reason text NOT NULL,
+  counter_account text NOT NULL,
+  reference text,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT point_ledger_pkey PRIMARY KEY (entry_id),
+  CONSTRAINT point_ledger_reason_reference_key UNIQUE (reason, reference),
+  CONSTRAINT point_ledger_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(user_id)
+);
 CREATE TABLE public.point_spend (
   spend_id bigint NOT NULL DEFAULT nextval('point_spend_spend_id_seq'::regclass),
   user_id bigint NOT NULL,
   item_id bigint NOT NULL,
   points_spent integer NOT NULL CHECK (points_spent > 0),
   spend_ts timestamp with time zone NOT NULL DEFAULT now(),
+  pointling_id bigint,
+  reversed_at timestamp with time zone,
   CONSTRAINT point_spend_pkey PRIMARY KEY (spend_id),
+  CONSTRAINT point_spend_pointling_id_fkey FOREIGN KEY (pointling_id) REFERENCES public.pointlings(pointling_id),
   CONSTRAINT point_spend_item_id_fkey FOREIGN KEY (item_id) REFERENCES public.items(item_id),
   CONSTRAINT point_spend_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(user_id)
 );
 CREATE TABLE public.pointling_colors (
   pointling_id bigint NOT NULL,
-  color_hex character NOT NULL,
+  color_hex character(7) NOT NULL,
   acquired_at timestamp with time zone NOT NULL DEFAULT now(),
   CONSTRAINT pointling_colors_pkey PRIMARY KEY (pointling_id, color_hex),
   CONSTRAINT pointling_colors_pointling_id_fkey FOREIGN KEY (pointling_id) REFERENCES public.pointlings(pointling_id)
@@ -46,22 +114,45 @@ CREATE TABLE public.pointlings (
   current_xp integer NOT NULL DEFAULT 0,
   required_xp integer NOT NULL DEFAULT 3,
   personality_id integer,
-  look_json jsonb NOT NULL DEFAULT '{}'::jsonb,
+  look_json jsonb NOT NULL DEFAULT '{"base_body": "classic", "slots": {}}'::jsonb,
   created_at timestamp with time zone NOT NULL DEFAULT now(),
   CONSTRAINT pointlings_pkey PRIMARY KEY (pointling_id),
+  CONSTRAINT pointlings_personality_id_fkey FOREIGN KEY (personality_id) REFERENCES public.personalities(personality_id),
   CONSTRAINT pointlings_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(user_id)
 );
+CREATE TABLE public.spend_reversals (
+  spend_id bigint NOT NULL,
+  user_id bigint NOT NULL,
+  pointling_id bigint,
+  item_id bigint NOT NULL,
+  points_refunded integer NOT NULL CHECK (points_refunded > 0),
+  ledger_entry_id bigint NOT NULL,
+  item_revoked boolean NOT NULL,
+  reason text NOT NULL,
+  actor text NOT NULL,
+  reversed_at timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT spend_reversals_pkey PRIMARY KEY (spend_id),
+  CONSTRAINT spend_reversals_spend_id_fkey FOREIGN KEY (spend_id) REFERENCES public.point_spend(spend_id),
+  CONSTRAINT spend_reversals_ledger_entry_id_fkey FOREIGN KEY (ledger_entry_id) REFERENCES public.point_ledger(entry_id)
+);
 CREATE TABLE public.users (
   user_id bigint NOT NULL,
   display_name text NOT NULL,
   point_balance bigint NOT NULL DEFAULT 0,
+  timezone text NOT NULL DEFAULT 'UTC'::text,
   created_at timestamp with time zone NOT NULL DEFAULT now(),
   CONSTRAINT users_pkey PRIMARY KEY (user_id)
 );
+CREATE TABLE public.xp_config_versions (
+  version integer NOT NULL DEFAULT nextval('xp_config_versions_version_seq'::regclass),
+  config jsonb NOT NULL,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT xp_config_versions_pkey PRIMARY KEY (version)
+);
 CREATE TABLE public.xp_events (
   event_id bigint NOT NULL DEFAULT nextval('xp_events_event_id_seq'::regclass),
   pointling_id bigint NOT NULL,
-  source USER-DEFINED NOT NULL,
+  source text NOT NULL,
   xp_amount integer NOT NULL,
   event_ts timestamp with time zone NOT NULL DEFAULT now(),
   CONSTRAINT xp_events_pkey PRIMARY KEY (event_id),
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+)
+
+// APIKeys are named static keys for back-office tools and services that
+// call the admin API without a user token.
+type APIKeys struct {
+	keys map[string][sha256.Size]byte
+}
+
+// ParseAPIKeys reads a comma-separated list of name:key pairs.
+func ParseAPIKeys(spec string) (APIKeys, error) {
+	set := APIKeys{keys: map[string][sha256.Size]byte{}}
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, key, ok := strings.Cut(pair, ":")
+		name, key = strings.TrimSpace(name), strings.TrimSpace(key)
+		if !ok || name == "" || key == "" {
+			return APIKeys{}, fmt.Errorf("api keys: want name:key, got %q", pair)
+		}
+		if _, dup := set.keys[name]; dup {
+			return APIKeys{}, fmt.Errorf("api keys: duplicate name %q", name)
+		}
+		set.keys[name] = sha256.Sum256([]byte(key))
+	}
+	return set, nil
+}
+
+// Authenticate returns an admin principal for a known key. Every key is
+// compared in constant time so timing does not reveal which one was close.
+func (k APIKeys) Authenticate(presented string) (Principal, bool) {
+	digest := sha256.Sum256([]byte(presented))
+	matched := ""
+	for name, want := range k.keys {
+		if subtle.ConstantTimeCompare(digest[:], want[:]) == 1 {
+			matched = name
+		}
+	}
+	if matched == "" {
+		return Principal{}, false
+	}
+	return Principal{APIKey: matched, Roles: []string{RoleAdmin}}, true
+}
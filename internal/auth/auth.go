@@ -0,0 +1,199 @@
+// Package auth verifies the bearer tokens sent by players and carries the
+// authenticated caller through request contexts.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	DefaultRolesClaim = "roles"
+
+	// RoleAdmin grants access to the /admin routes.
+	RoleAdmin = "admin"
+)
+
+var (
+	ErrInvalidToken      = errors.New("invalid token")
+	ErrNoVerificationKey = errors.New("no JWT secret or JWKS file configured")
+	ErrNoUserIDClaim     = errors.New("no JWT user ID claim configured")
+)
+
+// Config selects how tokens are verified. HS256Secret accepts Supabase-style
+// shared-secret tokens and JWKSFile accepts tokens signed by any of the
+// RSA or EC keys it lists; either or both may be set.
+type Config struct {
+	HS256Secret string
+	JWKSFile    string
+	Issuer      string
+	Audience    string
+	// UserIDClaim names the claim holding the numeric user ID, and is
+	// required: providers such as Supabase put a UUID in sub, so the ID has
+	// to come from a claim the provider is set up to add. Dots reach into
+	// nested claims, as in app_metadata.user_id.
+	UserIDClaim string
+	// RolesClaim names the claim holding a role or list of roles.
+	RolesClaim string
+}
+
+// Principal is the authenticated caller of a request. Players are identified
+// by UserID, and Subject is the token's sub as the provider issued it; callers
+// using an admin API key have APIKey set instead.
+type Principal struct {
+	UserID  int64
+	Subject string
+	Roles   []string
+	APIKey  string
+}
+
+// HasRole reports whether the caller was granted role.
+func (p Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Actor names the caller in audit records.
+func (p Principal) Actor() string {
+	if p.APIKey != "" {
+		return "api-key:" + p.APIKey
+	}
+	return "user:" + p.Subject
+}
+
+type Verifier struct {
+	secret      []byte
+	keys        *KeySet
+	userIDClaim []string
+	rolesClaim  string
+	parser      *jwt.Parser
+}
+
+func NewVerifier(cfg Config) (*Verifier, error) {
+	v := &Verifier{userIDClaim: strings.Split(cfg.UserIDClaim, "."), rolesClaim: cfg.RolesClaim}
+	if v.rolesClaim == "" {
+		v.rolesClaim = DefaultRolesClaim
+	}
+
+	var methods []string
+	if cfg.HS256Secret != "" {
+		v.secret = []byte(cfg.HS256Secret)
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if cfg.JWKSFile != "" {
+		keys, err := LoadKeySetFile(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		v.keys = keys
+		methods = append(methods, asymmetricMethods...)
+	}
+	if len(methods) == 0 {
+		return nil, ErrNoVerificationKey
+	}
+	if cfg.UserIDClaim == "" {
+		return nil, ErrNoUserIDClaim
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	v.parser = jwt.NewParser(opts...)
+	return v, nil
+}
+
+// Verify checks a token's signature and registered claims and returns the
+// caller it was issued to.
+func (v *Verifier) Verify(tokenString string) (Principal, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(tokenString, claims, v.key); err != nil {
+		return Principal{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	var rawID string
+	switch raw := nestedClaim(claims, v.userIDClaim).(type) {
+	case string:
+		rawID = raw
+	case float64:
+		rawID = strconv.FormatFloat(raw, 'f', -1, 64)
+	}
+	userID, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil || userID <= 0 {
+		return Principal{}, fmt.Errorf("%w: %s claim is not a user ID", ErrInvalidToken, strings.Join(v.userIDClaim, "."))
+	}
+	// The subject is only recorded, so any string the provider uses will do.
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		subject = rawID
+	}
+	return Principal{UserID: userID, Subject: subject, Roles: claimStrings(claims[v.rolesClaim])}, nil
+}
+
+// nestedClaim follows path through nested claim objects, returning nil when
+// any step is missing.
+func nestedClaim(claims jwt.MapClaims, path []string) interface{} {
+	var cur interface{} = map[string]interface{}(claims)
+	for _, key := range path {
+		obj, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		cur = obj[key]
+	}
+	return cur
+}
+
+// claimStrings reads a claim that may hold one string or a list of them.
+func claimStrings(raw interface{}) []string {
+	switch raw := raw.(type) {
+	case string:
+		return []string{raw}
+	case []interface{}:
+		var out []string
+		for _, r := range raw {
+			if s, ok := r.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+func (v *Verifier) key(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		return v.secret, nil
+	}
+	kid, _ := token.Header["kid"].(string)
+	return v.keys.Lookup(kid)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the authenticated caller.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the authenticated caller stored by WithPrincipal.
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
@@ -0,0 +1,190 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSecret = "test-secret-with-enough-entropy-0123456789"
+	// testSubject is a provider-issued subject, which is not a user ID.
+	testSubject = "6f1c2a9e-3b4d-4e8f-9a1b-2c3d4e5f6a7b"
+)
+
+func mintHS256(t *testing.T, secret string, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	require.NoError(t, err)
+	return token
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":     testSubject,
+		"user_id": "42",
+		"iss":     "https://auth.test",
+		"aud":     "pointlings",
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func withClaim(key string, value interface{}) jwt.MapClaims {
+	claims := validClaims()
+	if value == nil {
+		delete(claims, key)
+	} else {
+		claims[key] = value
+	}
+	return claims
+}
+
+func TestVerifyHS256(t *testing.T) {
+	verifier, err := NewVerifier(Config{
+		HS256Secret: testSecret,
+		Issuer:      "https://auth.test",
+		Audience:    "pointlings",
+		UserIDClaim: "user_id",
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantID  int64
+		wantErr bool
+	}{
+		{name: "valid", token: mintHS256(t, testSecret, validClaims()), wantID: 42},
+		{name: "numeric user ID", token: mintHS256(t, testSecret, withClaim("user_id", 42)), wantID: 42},
+		{name: "no subject", token: mintHS256(t, testSecret, withClaim("sub", nil)), wantID: 42},
+		{name: "wrong secret", token: mintHS256(t, "another-secret", validClaims()), wantErr: true},
+		{name: "expired", token: mintHS256(t, testSecret, withClaim("exp", time.Now().Add(-time.Minute).Unix())), wantErr: true},
+		{name: "no expiry", token: mintHS256(t, testSecret, withClaim("exp", nil)), wantErr: true},
+		{name: "wrong issuer", token: mintHS256(t, testSecret, withClaim("iss", "https://evil.test")), wantErr: true},
+		{name: "wrong audience", token: mintHS256(t, testSecret, withClaim("aud", "other")), wantErr: true},
+		{name: "non-numeric user ID", token: mintHS256(t, testSecret, withClaim("user_id", "4b1d-uuid")), wantErr: true},
+		{name: "no user ID", token: mintHS256(t, testSecret, withClaim("user_id", nil)), wantErr: true},
+		{name: "negative user ID", token: mintHS256(t, testSecret, withClaim("user_id", -1)), wantErr: true},
+		{name: "garbage", token: "not.a.jwt", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := verifier.Verify(tt.token)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidToken)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantID, principal.UserID)
+		})
+	}
+}
+
+func TestVerifyUUIDSubject(t *testing.T) {
+	verifier, err := NewVerifier(Config{HS256Secret: testSecret, UserIDClaim: "user_id"})
+	require.NoError(t, err)
+
+	principal, err := verifier.Verify(mintHS256(t, testSecret, validClaims()))
+	require.NoError(t, err)
+	require.Equal(t, int64(42), principal.UserID)
+	require.Equal(t, testSubject, principal.Subject)
+	require.Equal(t, "user:"+testSubject, principal.Actor())
+}
+
+func TestVerifyUserIDClaim(t *testing.T) {
+	// Supabase custom claims are usually added under app_metadata.
+	verifier, err := NewVerifier(Config{HS256Secret: testSecret, UserIDClaim: "app_metadata.user_id"})
+	require.NoError(t, err)
+
+	principal, err := verifier.Verify(mintHS256(t, testSecret, withClaim("app_metadata", map[string]interface{}{"user_id": 7})))
+	require.NoError(t, err)
+	require.Equal(t, int64(7), principal.UserID)
+	_, err = verifier.Verify(mintHS256(t, testSecret, validClaims()))
+	require.ErrorIs(t, err, ErrInvalidToken)
+	_, err = verifier.Verify(mintHS256(t, testSecret, withClaim("app_metadata", "7")))
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	// Providers that do issue numeric subjects can point the claim at sub.
+	verifier, err = NewVerifier(Config{HS256Secret: testSecret, UserIDClaim: "sub"})
+	require.NoError(t, err)
+	principal, err = verifier.Verify(mintHS256(t, testSecret, withClaim("sub", "9")))
+	require.NoError(t, err)
+	require.Equal(t, int64(9), principal.UserID)
+	require.Equal(t, "9", principal.Subject)
+	_, err = verifier.Verify(mintHS256(t, testSecret, validClaims()))
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestVerifyRejectsUnsignedTokens(t *testing.T) {
+	verifier, err := NewVerifier(Config{HS256Secret: testSecret, UserIDClaim: "user_id"})
+	require.NoError(t, err)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodNone, validClaims()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+	_, err = verifier.Verify(token)
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestVerifyJWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	jwks, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "key-1",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	})
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, jwks, 0o600))
+
+	verifier, err := NewVerifier(Config{JWKSFile: path, UserIDClaim: "user_id"})
+	require.NoError(t, err)
+
+	mint := func(signer *rsa.PrivateKey, kid string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, validClaims())
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(signer)
+		require.NoError(t, err)
+		return signed
+	}
+
+	principal, err := verifier.Verify(mint(key, "key-1"))
+	require.NoError(t, err)
+	require.Equal(t, int64(42), principal.UserID)
+
+	_, err = verifier.Verify(mint(other, "key-1"))
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = verifier.Verify(mint(key, "key-2"))
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	// An HS256 token must not be accepted when only a JWKS is configured.
+	_, err = verifier.Verify(mintHS256(t, testSecret, validClaims()))
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestNewVerifierRequiresAKey(t *testing.T) {
+	_, err := NewVerifier(Config{})
+	require.ErrorIs(t, err, ErrNoVerificationKey)
+}
+
+func TestNewVerifierRequiresAUserIDClaim(t *testing.T) {
+	_, err := NewVerifier(Config{HS256Secret: testSecret})
+	require.ErrorIs(t, err, ErrNoUserIDClaim)
+}
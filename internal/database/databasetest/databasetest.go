@@ -0,0 +1,105 @@
+// Package databasetest provides the Postgres that database-backed tests run
+// against: the server named by POINTLINGS_TEST_DATABASE_URL, or else an
+// embedded one started for the test binary.
+package databasetest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"my-pointlings-be/internal/database"
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/stretchr/testify/require"
+)
+
+// EnvURL names a Postgres URL to run against instead of the embedded server.
+const EnvURL = "POINTLINGS_TEST_DATABASE_URL"
+
+// Server is the Postgres for one test binary.
+type Server struct {
+	dsn  string
+	err  error
+	stop func()
+}
+
+// Start picks the test database. Call it from TestMain after flag.Parse and
+// Stop it once the tests have run. In -short mode without EnvURL no server
+// is started and Open skips.
+func Start() *Server {
+	if dsn := os.Getenv(EnvURL); dsn != "" {
+		return &Server{dsn: dsn}
+	}
+	if testing.Short() {
+		return &Server{}
+	}
+	dsn, stop, err := startEmbedded()
+	return &Server{dsn: dsn, err: err, stop: stop}
+}
+
+// Stop shuts down the embedded server, if one was started.
+func (s *Server) Stop() {
+	if s.stop != nil {
+		s.stop()
+	}
+}
+
+// Open connects to the server and applies every migration. A server that
+// could not start fails t; only -short skips.
+func (s *Server) Open(t *testing.T) *sql.DB {
+	t.Helper()
+	switch {
+	case s.err != nil:
+		t.Fatalf("embedded postgres: %v (set %s or run with -short)", s.err, EnvURL)
+	case s.dsn == "":
+		t.Skipf("%s not set and embedded postgres skipped in short mode", EnvURL)
+	}
+
+	db, err := sql.Open("pgx", s.dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	_, err = database.MigrateUp(context.Background(), db)
+	require.NoError(t, err)
+	return db
+}
+
+// startEmbedded runs a throwaway Postgres on a free port. The binaries are
+// downloaded on first use and cached by the embedded-postgres module.
+func startEmbedded() (string, func(), error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", nil, fmt.Errorf("find free port: %w", err)
+	}
+	port := uint32(listener.Addr().(*net.TCPAddr).Port)
+	listener.Close()
+
+	runtimePath, err := os.MkdirTemp("", "pointlings-pg-")
+	if err != nil {
+		return "", nil, fmt.Errorf("create runtime dir: %w", err)
+	}
+
+	cfg := embeddedpostgres.DefaultConfig().
+		Version(embeddedpostgres.V16).
+		Port(port).
+		RuntimePath(runtimePath).
+		StartTimeout(time.Minute).
+		Logger(nil)
+	pg := embeddedpostgres.NewDatabase(cfg)
+	if err := pg.Start(); err != nil {
+		os.RemoveAll(runtimePath)
+		return "", nil, fmt.Errorf("start embedded postgres: %w", err)
+	}
+
+	stop := func() {
+		pg.Stop()
+		os.RemoveAll(runtimePath)
+	}
+	return cfg.GetConnectionURL() + "?sslmode=disable", stop, nil
+}
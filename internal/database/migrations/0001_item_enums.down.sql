@@ -0,0 +1,3 @@
+DROP TYPE item_rarity;
+DROP TYPE item_slot;
+DROP TYPE item_category;
@@ -0,0 +1,16 @@
+-- Databases that predate the migrations already have these types, so each is
+-- only created when missing.
+DO $$ BEGIN
+  CREATE TYPE item_category AS ENUM ('ACCESSORY', 'FEATURE');
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+DO $$ BEGIN
+  CREATE TYPE item_slot AS ENUM ('HAT', 'SHOES', 'FACE', 'WINGS');
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+DO $$ BEGIN
+  CREATE TYPE item_rarity AS ENUM ('COMMON', 'RARE', 'EPIC', 'LEGENDARY');
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
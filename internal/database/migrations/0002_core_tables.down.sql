@@ -0,0 +1,14 @@
+DROP TABLE public.admin_audit_log;
+DROP TABLE public.idempotency_keys;
+DROP TABLE public.spend_reversals;
+DROP TABLE public.point_ledger;
+DROP TABLE public.point_spend;
+DROP TABLE public.level_rewards;
+DROP TABLE public.xp_events;
+DROP TABLE public.xp_config_versions;
+DROP TABLE public.pointling_colors;
+DROP TABLE public.pointling_items;
+DROP TABLE public.items;
+DROP TABLE public.pointlings;
+DROP TABLE public.personalities;
+DROP TABLE public.users;
@@ -0,0 +1,205 @@
+-- Databases that predate the migrations already have users, pointlings,
+-- items, pointling_items, pointling_colors, point_spend and xp_events. Those
+-- are kept as they are and brought up to date at the end of this file.
+
+CREATE TABLE IF NOT EXISTS public.users (
+  user_id bigint NOT NULL,
+  display_name text NOT NULL,
+  point_balance bigint NOT NULL DEFAULT 0 CHECK (point_balance >= 0),
+  timezone text NOT NULL DEFAULT 'UTC',
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT users_pkey PRIMARY KEY (user_id)
+);
+
+CREATE TABLE IF NOT EXISTS public.personalities (
+  personality_id integer GENERATED BY DEFAULT AS IDENTITY,
+  code text NOT NULL,
+  name text NOT NULL,
+  traits jsonb NOT NULL DEFAULT '[]'::jsonb,
+  idle_animation_ids jsonb NOT NULL DEFAULT '[]'::jsonb,
+  dialogue_tags jsonb NOT NULL DEFAULT '[]'::jsonb,
+  weight integer NOT NULL DEFAULT 1 CHECK (weight >= 0),
+  xp_bonus_percent jsonb NOT NULL DEFAULT '{}'::jsonb,
+  CONSTRAINT personalities_pkey PRIMARY KEY (personality_id),
+  CONSTRAINT personalities_code_key UNIQUE (code)
+);
+
+CREATE TABLE IF NOT EXISTS public.pointlings (
+  pointling_id bigint GENERATED BY DEFAULT AS IDENTITY,
+  user_id bigint NOT NULL,
+  nickname text,
+  level integer NOT NULL DEFAULT 1 CHECK (level >= 1),
+  current_xp integer NOT NULL DEFAULT 0 CHECK (current_xp >= 0),
+  required_xp integer NOT NULL DEFAULT 3,
+  personality_id integer,
+  look_json jsonb NOT NULL DEFAULT '{"base_body": "classic", "slots": {}}'::jsonb,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT pointlings_pkey PRIMARY KEY (pointling_id),
+  CONSTRAINT pointlings_personality_id_fkey FOREIGN KEY (personality_id) REFERENCES public.personalities(personality_id),
+  CONSTRAINT pointlings_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(user_id)
+);
+
+CREATE TABLE IF NOT EXISTS public.items (
+  item_id bigint GENERATED BY DEFAULT AS IDENTITY,
+  category item_category NOT NULL,
+  slot item_slot,
+  asset_id text NOT NULL,
+  name text NOT NULL,
+  rarity item_rarity NOT NULL,
+  price_points integer CHECK (price_points >= 0),
+  unlock_level integer CHECK (unlock_level >= 1),
+  CONSTRAINT items_pkey PRIMARY KEY (item_id),
+  -- Accessories are worn in a slot; features are drawn on the body.
+  CONSTRAINT items_slot_matches_category CHECK ((category = 'ACCESSORY') = (slot IS NOT NULL))
+);
+
+CREATE TABLE IF NOT EXISTS public.pointling_items (
+  pointling_id bigint NOT NULL,
+  item_id bigint NOT NULL,
+  acquired_at timestamp with time zone NOT NULL DEFAULT now(),
+  equipped boolean NOT NULL DEFAULT false,
+  CONSTRAINT pointling_items_pkey PRIMARY KEY (pointling_id, item_id),
+  CONSTRAINT pointling_items_pointling_id_fkey FOREIGN KEY (pointling_id) REFERENCES public.pointlings(pointling_id),
+  CONSTRAINT pointling_items_item_id_fkey FOREIGN KEY (item_id) REFERENCES public.items(item_id)
+);
+
+CREATE TABLE IF NOT EXISTS public.pointling_colors (
+  pointling_id bigint NOT NULL,
+  color_hex character(7) NOT NULL,
+  acquired_at timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT pointling_colors_pkey PRIMARY KEY (pointling_id, color_hex),
+  CONSTRAINT pointling_colors_pointling_id_fkey FOREIGN KEY (pointling_id) REFERENCES public.pointlings(pointling_id)
+);
+
+CREATE TABLE IF NOT EXISTS public.xp_config_versions (
+  version integer GENERATED BY DEFAULT AS IDENTITY,
+  config jsonb NOT NULL,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT xp_config_versions_pkey PRIMARY KEY (version)
+);
+
+CREATE TABLE IF NOT EXISTS public.xp_events (
+  event_id bigint GENERATED BY DEFAULT AS IDENTITY,
+  pointling_id bigint NOT NULL,
+  source text NOT NULL,
+  xp_amount integer NOT NULL CHECK (xp_amount > 0),
+  event_ts timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT xp_events_pkey PRIMARY KEY (event_id),
+  CONSTRAINT xp_events_pointling_id_fkey FOREIGN KEY (pointling_id) REFERENCES public.pointlings(pointling_id)
+);
+
+CREATE TABLE IF NOT EXISTS public.level_rewards (
+  reward_id bigint GENERATED BY DEFAULT AS IDENTITY,
+  pointling_id bigint NOT NULL,
+  level integer NOT NULL,
+  options jsonb NOT NULL,
+  chosen_option jsonb,
+  offered_at timestamp with time zone NOT NULL DEFAULT now(),
+  expires_at timestamp with time zone NOT NULL,
+  claimed_at timestamp with time zone,
+  CONSTRAINT level_rewards_pkey PRIMARY KEY (reward_id),
+  CONSTRAINT level_rewards_pointling_level_key UNIQUE (pointling_id, level),
+  CONSTRAINT level_rewards_pointling_id_fkey FOREIGN KEY (pointling_id) REFERENCES public.pointlings(pointling_id)
+);
+
+CREATE TABLE IF NOT EXISTS public.point_spend (
+  spend_id bigint GENERATED BY DEFAULT AS IDENTITY,
+  user_id bigint NOT NULL,
+  item_id bigint NOT NULL,
+  points_spent integer NOT NULL CHECK (points_spent > 0),
+  spend_ts timestamp with time zone NOT NULL DEFAULT now(),
+  pointling_id bigint,
+  reversed_at timestamp with time zone,
+  CONSTRAINT point_spend_pkey PRIMARY KEY (spend_id),
+  CONSTRAINT point_spend_pointling_id_fkey FOREIGN KEY (pointling_id) REFERENCES public.pointlings(pointling_id),
+  CONSTRAINT point_spend_item_id_fkey FOREIGN KEY (item_id) REFERENCES public.items(item_id),
+  CONSTRAINT point_spend_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(user_id)
+);
+
+CREATE TABLE IF NOT EXISTS public.point_ledger (
+  entry_id bigint GENERATED BY DEFAULT AS IDENTITY,
+  user_id bigint NOT NULL,
+  delta bigint NOT NULL CHECK (delta <> 0),
+  balance_after bigint NOT NULL CHECK (balance_after >= 0),
+  reason text NOT NULL,
+  counter_account text NOT NULL,
+  reference text,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT point_ledger_pkey PRIMARY KEY (entry_id),
+  CONSTRAINT point_ledger_reason_reference_key UNIQUE (reason, reference),
+  CONSTRAINT point_ledger_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(user_id)
+);
+
+CREATE TABLE IF NOT EXISTS public.spend_reversals (
+  spend_id bigint NOT NULL,
+  user_id bigint NOT NULL,
+  pointling_id bigint,
+  item_id bigint NOT NULL,
+  points_refunded integer NOT NULL CHECK (points_refunded > 0),
+  ledger_entry_id bigint NOT NULL,
+  item_revoked boolean NOT NULL,
+  reason text NOT NULL,
+  actor text NOT NULL,
+  reversed_at timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT spend_reversals_pkey PRIMARY KEY (spend_id),
+  CONSTRAINT spend_reversals_spend_id_fkey FOREIGN KEY (spend_id) REFERENCES public.point_spend(spend_id),
+  CONSTRAINT spend_reversals_ledger_entry_id_fkey FOREIGN KEY (ledger_entry_id) REFERENCES public.point_ledger(entry_id)
+);
+
+CREATE TABLE IF NOT EXISTS public.idempotency_keys (
+  idem_key text NOT NULL,
+  request_hash text NOT NULL,
+  status_code integer,
+  content_type text,
+  response_body bytea,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  expires_at timestamp with time zone NOT NULL,
+  CONSTRAINT idempotency_keys_pkey PRIMARY KEY (idem_key)
+);
+
+CREATE TABLE IF NOT EXISTS public.admin_audit_log (
+  audit_id bigint GENERATED BY DEFAULT AS IDENTITY,
+  actor text NOT NULL,
+  action text NOT NULL,
+  target_type text NOT NULL,
+  target_id text NOT NULL,
+  before jsonb,
+  after jsonb,
+  created_at timestamp with time zone NOT NULL DEFAULT now(),
+  CONSTRAINT admin_audit_log_pkey PRIMARY KEY (audit_id)
+);
+
+-- Bring pre-migration tables up to date. Each step is a no-op on a database
+-- these migrations created.
+ALTER TABLE public.users
+  ADD COLUMN IF NOT EXISTS timezone text NOT NULL DEFAULT 'UTC';
+
+ALTER TABLE public.pointlings
+  ALTER COLUMN look_json SET DEFAULT '{"base_body": "classic", "slots": {}}'::jsonb;
+
+-- There was no personality catalog before, so existing personality IDs point
+-- at nothing; clear them before the foreign key goes on.
+DO $$ BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'pointlings_personality_id_fkey') THEN
+    UPDATE public.pointlings SET personality_id = NULL
+    WHERE personality_id NOT IN (SELECT personality_id FROM public.personalities);
+    ALTER TABLE public.pointlings ADD CONSTRAINT pointlings_personality_id_fkey
+      FOREIGN KEY (personality_id) REFERENCES public.personalities(personality_id);
+  END IF;
+END $$;
+
+-- Some early schemas named the item category column item_category.
+DO $$ BEGIN
+  IF EXISTS (SELECT 1 FROM information_schema.columns
+             WHERE table_schema = 'public' AND table_name = 'items' AND column_name = 'item_category') THEN
+    ALTER TABLE public.items RENAME COLUMN item_category TO category;
+  END IF;
+END $$;
+
+ALTER TABLE public.point_spend
+  ADD COLUMN IF NOT EXISTS pointling_id bigint
+    CONSTRAINT point_spend_pointling_id_fkey REFERENCES public.pointlings(pointling_id),
+  ADD COLUMN IF NOT EXISTS reversed_at timestamp with time zone;
+
+-- XP sources are configurable now, so they are no longer an enum.
+ALTER TABLE public.xp_events ALTER COLUMN source TYPE text USING source::text;
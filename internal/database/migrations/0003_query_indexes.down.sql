@@ -0,0 +1,9 @@
+DROP INDEX public.admin_audit_log_target_idx;
+DROP INDEX public.idempotency_keys_expires_at_idx;
+DROP INDEX public.point_ledger_user_entry_idx;
+DROP INDEX public.point_spend_user_ts_idx;
+DROP INDEX public.level_rewards_pending_idx;
+DROP INDEX public.xp_events_pointling_ts_idx;
+DROP INDEX public.items_unlock_level_idx;
+DROP INDEX public.pointling_items_item_id_idx;
+DROP INDEX public.pointlings_user_id_idx;
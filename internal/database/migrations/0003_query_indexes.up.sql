@@ -0,0 +1,10 @@
+-- Each index backs a lookup or page the repository runs.
+CREATE INDEX IF NOT EXISTS pointlings_user_id_idx ON public.pointlings (user_id);
+CREATE INDEX IF NOT EXISTS pointling_items_item_id_idx ON public.pointling_items (item_id);
+CREATE INDEX IF NOT EXISTS items_unlock_level_idx ON public.items (unlock_level) WHERE unlock_level IS NOT NULL;
+CREATE INDEX IF NOT EXISTS xp_events_pointling_ts_idx ON public.xp_events (pointling_id, event_ts DESC, event_id DESC);
+CREATE INDEX IF NOT EXISTS level_rewards_pending_idx ON public.level_rewards (pointling_id) WHERE claimed_at IS NULL;
+CREATE INDEX IF NOT EXISTS point_spend_user_ts_idx ON public.point_spend (user_id, spend_ts DESC, spend_id DESC);
+CREATE INDEX IF NOT EXISTS point_ledger_user_entry_idx ON public.point_ledger (user_id, entry_id DESC);
+CREATE INDEX IF NOT EXISTS idempotency_keys_expires_at_idx ON public.idempotency_keys (expires_at);
+CREATE INDEX IF NOT EXISTS admin_audit_log_target_idx ON public.admin_audit_log (target_type, target_id, audit_id DESC);
@@ -0,0 +1,6 @@
+UPDATE public.pointlings SET personality_id = NULL
+WHERE personality_id IN (
+  SELECT personality_id FROM public.personalities
+  WHERE code IN ('playful', 'thrifty', 'loyal', 'sleepy')
+);
+DELETE FROM public.personalities WHERE code IN ('playful', 'thrifty', 'loyal', 'sleepy');
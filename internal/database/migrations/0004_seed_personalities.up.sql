@@ -0,0 +1,9 @@
+-- Starter personalities, so new pointlings get one on a fresh database.
+-- Keep in step with models.StarterPersonalities, which seeds the in-memory
+-- repository.
+INSERT INTO public.personalities (code, name, traits, idle_animation_ids, dialogue_tags, weight, xp_bonus_percent) VALUES
+  ('playful', 'Playful', '["energetic", "curious"]', '["bounce", "spin"]', '["games", "jokes"]', 3, '{"PLAY": 20}'),
+  ('thrifty', 'Thrifty', '["careful", "tidy"]', '["count_coins"]', '["savings"]', 3, '{"RECEIPT": 20}'),
+  ('loyal', 'Loyal', '["steady", "warm"]', '["wave"]', '["greetings"]', 2, '{"DAILY": 50}'),
+  ('sleepy', 'Sleepy', '["calm", "dreamy"]', '["yawn", "nap"]', '["dreams"]', 2, '{}')
+ON CONFLICT (code) DO NOTHING;
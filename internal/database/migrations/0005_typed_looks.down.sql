@@ -0,0 +1,10 @@
+-- Puts looks back in the free-form shape older code wrote, with each worn
+-- asset under its lower-case slot name. Legacy values that 0005 replaced as
+-- malformed are gone for good, but 0005 rebuilds slots from pointling_items,
+-- so migrating up again yields the same looks.
+UPDATE public.pointlings
+SET look_json = (look_json - 'slots') || COALESCE((
+  SELECT jsonb_object_agg(lower(s.key), s.value)
+  FROM jsonb_each(look_json->'slots') s
+), '{}'::jsonb)
+WHERE jsonb_typeof(look_json->'slots') = 'object';
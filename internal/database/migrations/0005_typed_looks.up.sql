@@ -0,0 +1,30 @@
+-- Rewrites look_json in the typed shape models.Look stores, the same way
+-- models.BuildLook does: base_body and color carry over when valid, equipped
+-- accessories fill their slots and every owned feature is listed, sorted.
+-- Re-running it is harmless.
+UPDATE public.pointlings p
+SET look_json = jsonb_strip_nulls(jsonb_build_object(
+  'base_body', CASE
+    WHEN p.look_json->>'base_body' ~ '^[a-z0-9_-]{1,32}$' THEN p.look_json->>'base_body'
+    ELSE 'classic'
+  END,
+  'color', CASE
+    WHEN upper(p.look_json->>'color') ~ '^#[0-9A-F]{6}$' THEN upper(p.look_json->>'color')
+  END,
+  'slots', COALESCE((
+    SELECT jsonb_object_agg(i.slot::text, i.asset_id ORDER BY pi.acquired_at)
+    FROM public.pointling_items pi
+    JOIN public.items i ON i.item_id = pi.item_id
+    WHERE pi.pointling_id = p.pointling_id
+    AND pi.equipped
+    AND i.category = 'ACCESSORY'
+    AND i.slot IS NOT NULL
+  ), '{}'::jsonb),
+  'features', (
+    SELECT jsonb_agg(i.asset_id ORDER BY i.asset_id COLLATE "C")
+    FROM public.pointling_items pi
+    JOIN public.items i ON i.item_id = pi.item_id
+    WHERE pi.pointling_id = p.pointling_id
+    AND i.category = 'FEATURE'
+  )
+));
@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"net/http"
+
+	models "my-pointlings-be/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *PointlingHandler) ListAuditLog(c *gin.Context) {
+	var query models.AuditLogRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		fail(c, bindingError(err))
+		return
+	}
+	log, err := h.service.ListAuditLog(c.Request.Context(), query)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, log)
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	"my-pointlings-be/internal/auth"
+	models "my-pointlings-be/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// authorizeUser reports whether the authenticated caller is userID or an
+// admin. When not, the request has already failed with 401 or 403.
+func authorizeUser(c *gin.Context, userID string) bool {
+	principal, ok := auth.FromContext(c.Request.Context())
+	if !ok {
+		fail(c, models.ErrUnauthenticated)
+		return false
+	}
+	if principal.HasRole(auth.RoleAdmin) {
+		return true
+	}
+	id, err := models.ParseID("user_id", strings.TrimSpace(userID))
+	if err != nil {
+		fail(c, err)
+		return false
+	}
+	if id != principal.UserID {
+		fail(c, fmt.Errorf("%w: caller does not own this user", models.ErrForbidden))
+		return false
+	}
+	return true
+}
+
+// authorizePointling reports whether the authenticated caller owns
+// pointlingID or is an admin. When not, the request has already failed.
+func (h *PointlingHandler) authorizePointling(c *gin.Context, pointlingID string) bool {
+	principal, ok := auth.FromContext(c.Request.Context())
+	if !ok {
+		fail(c, models.ErrUnauthenticated)
+		return false
+	}
+	if principal.HasRole(auth.RoleAdmin) {
+		return true
+	}
+	ownerID, err := h.service.GetPointlingOwnerID(c.Request.Context(), pointlingID)
+	if err != nil {
+		fail(c, err)
+		return false
+	}
+	if ownerID != principal.UserID {
+		fail(c, fmt.Errorf("%w: caller does not own this pointling", models.ErrForbidden))
+		return false
+	}
+	return true
+}
+
+// bindPathID sets *body to the param route ID so a request can only act on
+// the resource in its path. A body that names a different ID fails with 400.
+func bindPathID(c *gin.Context, param string, body *string) bool {
+	id := strings.TrimSpace(strings.TrimPrefix(c.Param(param), "/"))
+	if *body != "" && strings.TrimSpace(*body) != id {
+		var fe models.FieldErrors
+		fe.Add(param, "must match the path")
+		fail(c, fe.Err())
+		return false
+	}
+	*body = id
+	return true
+}
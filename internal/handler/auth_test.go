@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"my-pointlings-be/internal/auth"
+	"my-pointlings-be/internal/middleware"
+	"my-pointlings-be/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "handler-test-secret-0123456789abcdef"
+
+// ownerService answers pointling ownership lookups from a fixed map; every
+// other service method is left unimplemented.
+type ownerService struct {
+	service.API
+	owners map[string]int64
+}
+
+func (s ownerService) GetPointlingOwnerID(c context.Context, pointlingID string) (int64, error) {
+	return s.owners[pointlingID], nil
+}
+
+func mintToken(t *testing.T, userID string, roles ...string) string {
+	t.Helper()
+	claims := jwt.MapClaims{
+		"sub":     "subject-" + userID,
+		"user_id": userID,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}
+	if len(roles) > 0 {
+		claims["roles"] = roles
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+	require.NoError(t, err)
+	return token
+}
+
+func TestOwnershipIsEnforced(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	verifier, err := auth.NewVerifier(auth.Config{HS256Secret: testSecret, UserIDClaim: "user_id"})
+	require.NoError(t, err)
+
+	h := New(ownerService{owners: map[string]int64{"10": 1, "20": 2}})
+	r := gin.New()
+	r.Use(middleware.Errors())
+	r.Use(middleware.Authenticate(verifier))
+	r.GET("/users/:user_id", func(c *gin.Context) {
+		if authorizeUser(c, c.Param("user_id")) {
+			c.Status(http.StatusNoContent)
+		}
+	})
+	r.GET("/pointlings/:pointling_id", func(c *gin.Context) {
+		if h.authorizePointling(c, c.Param("pointling_id")) {
+			c.Status(http.StatusNoContent)
+		}
+	})
+
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		want   int
+	}{
+		{name: "no token", path: "/users/1", want: http.StatusUnauthorized},
+		{name: "wrong scheme", path: "/users/1", header: "Basic " + mintToken(t, "1"), want: http.StatusUnauthorized},
+		{name: "bad signature", path: "/users/1", header: "Bearer " + mintToken(t, "1") + "x", want: http.StatusUnauthorized},
+		{name: "own user", path: "/users/1", header: "Bearer " + mintToken(t, "1"), want: http.StatusNoContent},
+		{name: "other user", path: "/users/2", header: "Bearer " + mintToken(t, "1"), want: http.StatusForbidden},
+		{name: "own pointling", path: "/pointlings/10", header: "Bearer " + mintToken(t, "1"), want: http.StatusNoContent},
+		{name: "other pointling", path: "/pointlings/20", header: "Bearer " + mintToken(t, "1"), want: http.StatusForbidden},
+		{name: "admin on other user", path: "/users/2", header: "Bearer " + mintToken(t, "1", auth.RoleAdmin), want: http.StatusNoContent},
+		{name: "admin on other pointling", path: "/pointlings/20", header: "Bearer " + mintToken(t, "1", auth.RoleAdmin), want: http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			require.Equal(t, tt.want, w.Code)
+		})
+	}
+}
+
+func TestRequireAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	verifier, err := auth.NewVerifier(auth.Config{HS256Secret: testSecret, UserIDClaim: "user_id"})
+	require.NoError(t, err)
+	keys, err := auth.ParseAPIKeys("support:s3cret-key, billing:other-key")
+	require.NoError(t, err)
+
+	var actor string
+	r := gin.New()
+	r.Use(middleware.Errors())
+	r.Use(middleware.RequireAdmin(verifier, keys))
+	r.GET("/admin/ping", func(c *gin.Context) {
+		principal, _ := auth.FromContext(c.Request.Context())
+		actor = principal.Actor()
+		c.Status(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name      string
+		headers   map[string]string
+		want      int
+		wantActor string
+	}{
+		{name: "nothing", want: http.StatusUnauthorized},
+		{name: "player token", headers: map[string]string{"Authorization": "Bearer " + mintToken(t, "1")}, want: http.StatusForbidden},
+		{name: "admin token", headers: map[string]string{"Authorization": "Bearer " + mintToken(t, "1", auth.RoleAdmin)}, want: http.StatusNoContent, wantActor: "user:subject-1"},
+		{name: "api key", headers: map[string]string{middleware.APIKeyHeader: "s3cret-key"}, want: http.StatusNoContent, wantActor: "api-key:support"},
+		{name: "wrong api key", headers: map[string]string{middleware.APIKeyHeader: "guess"}, want: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actor = ""
+			req := httptest.NewRequest(http.MethodGet, "/admin/ping", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			require.Equal(t, tt.want, w.Code)
+			require.Equal(t, tt.wantActor, actor)
+		})
+	}
+}
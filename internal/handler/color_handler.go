@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	models "my-pointlings-be/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *PointlingHandler) ListColors(c *gin.Context) {
+	pointlingID := strings.TrimSpace(strings.TrimPrefix(c.Param("pointling_id"), "/"))
+	if !h.authorizePointling(c, pointlingID) {
+		return
+	}
+	colors, err := h.service.ListColors(c.Request.Context(), pointlingID)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, colors)
+}
+
+func (h *PointlingHandler) SetColor(c *gin.Context) {
+	var color models.SetColorRequest
+	if err := c.ShouldBindJSON(&color); err != nil {
+		fail(c, bindingError(err))
+		return
+	}
+	if !bindPathID(c, "pointling_id", &color.PointlingID) || !h.authorizePointling(c, color.PointlingID) {
+		return
+	}
+	pointling, err := h.service.SetColor(c.Request.Context(), color)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pointling)
+}
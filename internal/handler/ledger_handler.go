@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	models "my-pointlings-be/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *PointlingHandler) GetLedger(c *gin.Context) {
+	var query models.LedgerRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		fail(c, bindingError(err))
+		return
+	}
+	query.UserID = strings.TrimSpace(strings.TrimPrefix(c.Param("user_id"), "/"))
+	if !authorizeUser(c, query.UserID) {
+		return
+	}
+	ledger, err := h.service.GetLedger(c.Request.Context(), query)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ledger)
+}
+
+func (h *PointlingHandler) ReconcilePoints(c *gin.Context) {
+	userID := strings.TrimSpace(strings.TrimPrefix(c.Param("user_id"), "/"))
+	if !authorizeUser(c, userID) {
+		return
+	}
+	res, err := h.service.ReconcilePoints(c.Request.Context(), userID)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *PointlingHandler) BackfillLedger(c *gin.Context) {
+	res, err := h.service.BackfillLedger(c.Request.Context())
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *PointlingHandler) GetRenderManifest(c *gin.Context) {
+	pointlingID := strings.TrimSpace(strings.TrimPrefix(c.Param("pointling_id"), "/"))
+	if !h.authorizePointling(c, pointlingID) {
+		return
+	}
+	manifest, err := h.service.GetRenderManifest(c.Request.Context(), pointlingID)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+
+	c.Header("ETag", manifest.ETag)
+	c.Header("Cache-Control", "private, no-cache")
+	if etagMatches(c.GetHeader("If-None-Match"), manifest.ETag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.JSON(http.StatusOK, manifest)
+}
+
+// etagMatches reports whether an If-None-Match header names etag. Weak
+// validators compare equal to their strong form, per RFC 9110.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"my-pointlings-be/internal/auth"
+	"my-pointlings-be/internal/middleware"
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository/memory"
+	"my-pointlings-be/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRenderManifestConditional(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	verifier, err := auth.NewVerifier(auth.Config{HS256Secret: testSecret, UserIDClaim: "user_id"})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	repo := memory.New()
+	require.NoError(t, repo.CreateUser(ctx, &models.User{UserID: 1, DisplayName: "one"}))
+	p := models.NewPointling(1, nil)
+	require.NoError(t, repo.CreatePointling(ctx, p))
+	slot := models.SlotHat
+	hat := &models.Item{Name: "Hat", Category: models.CategoryAccessory, Slot: &slot, AssetID: "hat", Rarity: models.RarityCommon}
+	require.NoError(t, repo.CreateItem(ctx, hat))
+	require.NoError(t, repo.AddItem(ctx, p.PointlingID, hat.ItemID))
+
+	svc := service.New(repo)
+	h := New(svc)
+	r := gin.New()
+	r.Use(middleware.Errors())
+	r.Use(middleware.Authenticate(verifier))
+	r.GET("/pointlings/:pointling_id/render", h.GetRenderManifest)
+
+	path := "/pointlings/" + strconv.FormatInt(p.PointlingID, 10) + "/render"
+	token := "Bearer " + mintToken(t, "1")
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", token)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	require.Equal(t, "private, no-cache", w.Header().Get("Cache-Control"))
+
+	for _, header := range []string{etag, "W/" + etag, `"stale", ` + etag, "*"} {
+		w = get(header)
+		require.Equal(t, http.StatusNotModified, w.Code, header)
+		require.Empty(t, w.Body.String())
+		require.Equal(t, etag, w.Header().Get("ETag"))
+	}
+	require.Equal(t, http.StatusOK, get(`"stale"`).Code)
+
+	// Wearing the hat changes the look, so the old tag no longer matches.
+	_, err = svc.ToggleEquipped(ctx, models.ToggleEquippedRequest{
+		PointlingID: strconv.FormatInt(p.PointlingID, 10),
+		ItemID:      strconv.FormatInt(hat.ItemID, 10),
+		Equipped:    true,
+	})
+	require.NoError(t, err)
+	w = get(etag)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotEqual(t, etag, w.Header().Get("ETag"))
+	require.Contains(t, w.Body.String(), `"asset_id":"hat"`)
+}
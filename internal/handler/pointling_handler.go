@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -19,6 +20,7 @@ type API interface {
 	CreateUser(c *gin.Context)
 	GetUser(c *gin.Context)
 	UpdateUserPoints(c *gin.Context)
+	UpdateUserTimezone(c *gin.Context)
 	CreatePointling(c *gin.Context)
 	GetPointling(c *gin.Context)
 	AddXP(c *gin.Context)
@@ -28,9 +30,25 @@ type API interface {
 	GetItem(c *gin.Context)
 	CreateItem(c *gin.Context)
 	GetInventory(c *gin.Context)
-	AcquireItem(c *gin.Context)
 	ToggleEquipped(c *gin.Context)
-	SpendPoints(c *gin.Context)
+	GetPendingRewards(c *gin.Context)
+	ClaimReward(c *gin.Context)
+	ListColors(c *gin.Context)
+	SetColor(c *gin.Context)
+	GetXPHistory(c *gin.Context)
+	GetXPLimits(c *gin.Context)
+	GetXPConfig(c *gin.Context)
+	ListXPConfigVersions(c *gin.Context)
+	UpdateXPConfig(c *gin.Context)
+	ListPersonalities(c *gin.Context)
+	GetRenderManifest(c *gin.Context)
+	PurchaseItem(c *gin.Context)
+	GetLedger(c *gin.Context)
+	ReconcilePoints(c *gin.Context)
+	BackfillLedger(c *gin.Context)
+	GetSpendHistory(c *gin.Context)
+	RefundSpend(c *gin.Context)
+	ListAuditLog(c *gin.Context)
 }
 
 func New(service service.API) *PointlingHandler {
@@ -38,31 +56,40 @@ func New(service service.API) *PointlingHandler {
 }
 
 func (h *PointlingHandler) ListUsers(c *gin.Context) {
-	if _, err := h.service.ListUsers(c.Request.Context()); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	users, err := h.service.ListUsers(c.Request.Context())
+	if err != nil {
+		fail(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "listUsers"})
+	c.JSON(http.StatusOK, users)
 }
 
 func (h *PointlingHandler) CreateUser(c *gin.Context) {
 	var user models.CreateUserRequest
 	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		fail(c, bindingError(err))
+		return
+	}
+	if !authorizeUser(c, user.UserID) {
 		return
 	}
-	if _, serviceErr := h.service.CreateUser(c.Request.Context(), user); serviceErr != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": serviceErr.Error()})
+	created, serviceErr := h.service.CreateUser(c.Request.Context(), user)
+	if serviceErr != nil {
+		fail(c, serviceErr)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "createUser"})
+	c.Header("Location", fmt.Sprintf("/api/users/%d", created.UserID))
+	c.JSON(http.StatusCreated, created)
 }
 
 func (h *PointlingHandler) GetUser(c *gin.Context) {
-	userID := strings.TrimSpace(strings.ToUpper(strings.TrimPrefix(c.Param("user_id"), "/")))
+	userID := strings.TrimSpace(strings.TrimPrefix(c.Param("user_id"), "/"))
+	if !authorizeUser(c, userID) {
+		return
+	}
 	user, serviceErr := h.service.GetUser(c.Request.Context(), userID)
 	if serviceErr != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": serviceErr.Error()})
+		fail(c, serviceErr)
 		return
 	}
 	c.JSON(http.StatusOK, user)
@@ -71,48 +98,93 @@ func (h *PointlingHandler) GetUser(c *gin.Context) {
 func (h *PointlingHandler) UpdateUserPoints(c *gin.Context) {
 	var pointling models.UpdateUserPointsRequest
 	if err := c.ShouldBindJSON(&pointling); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		fail(c, bindingError(err))
 		return
 	}
-	if _, err := h.service.UpdateUserPoints(c.Request.Context(), pointling); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if !bindPathID(c, "user_id", &pointling.UserID) {
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "updateUserPoints"})
+	updated, err := h.service.UpdateUserPoints(c.Request.Context(), pointling)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *PointlingHandler) UpdateUserTimezone(c *gin.Context) {
+	var user models.UpdateUserTimezoneRequest
+	if err := c.ShouldBindJSON(&user); err != nil {
+		fail(c, bindingError(err))
+		return
+	}
+	if !bindPathID(c, "user_id", &user.UserID) || !authorizeUser(c, user.UserID) {
+		return
+	}
+	updated, err := h.service.UpdateUserTimezone(c.Request.Context(), user)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
 }
 
 func (h *PointlingHandler) CreatePointling(c *gin.Context) {
 	var pointling models.CreatePointlingRequest
 	if err := c.ShouldBindJSON(&pointling); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		fail(c, bindingError(err))
 		return
 	}
-	if _, err := h.service.CreatePointling(c.Request.Context(), pointling); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if !authorizeUser(c, pointling.UserID) {
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "createPointling"})
+	created, err := h.service.CreatePointling(c.Request.Context(), pointling)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.Header("Location", fmt.Sprintf("/api/pointlings/%d", created.PointlingID))
+	c.JSON(http.StatusCreated, created)
 }
 
 func (h *PointlingHandler) GetPointling(c *gin.Context) {
-	pointlingID := strings.TrimSpace(strings.ToUpper(strings.TrimPrefix(c.Param("pointling_id"), "/")))
-	pointling, serviceErr := h.service.GetPointling(c.Request.Context(), pointlingID)
+	pointlingID := strings.TrimSpace(strings.TrimPrefix(c.Param("pointling_id"), "/"))
+	if !h.authorizePointling(c, pointlingID) {
+		return
+	}
+	var expand []string
+	if raw := c.Query("expand"); raw != "" {
+		expand = strings.Split(raw, ",")
+	}
+	pointling, serviceErr := h.service.GetPointling(c.Request.Context(), pointlingID, expand)
 	if serviceErr != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": serviceErr.Error()})
+		fail(c, serviceErr)
 		return
 	}
 	c.JSON(http.StatusOK, pointling)
 }
 
+func (h *PointlingHandler) ListPersonalities(c *gin.Context) {
+	personalities, err := h.service.ListPersonalities(c.Request.Context())
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, personalities)
+}
+
 func (h *PointlingHandler) AddXP(c *gin.Context) {
 	var pointling models.AddXPRequest
 	if err := c.ShouldBindJSON(&pointling); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		fail(c, bindingError(err))
+		return
+	}
+	if !bindPathID(c, "pointling_id", &pointling.PointlingID) || !h.authorizePointling(c, pointling.PointlingID) {
 		return
 	}
 	response, err := h.service.AddXP(c.Request.Context(), pointling)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		fail(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, response)
@@ -121,21 +193,28 @@ func (h *PointlingHandler) AddXP(c *gin.Context) {
 func (h *PointlingHandler) UpdateNickname(c *gin.Context) {
 	var pointling models.UpdateNicknameRequest
 	if err := c.ShouldBindJSON(&pointling); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		fail(c, bindingError(err))
 		return
 	}
-	if _, err := h.service.UpdateNickname(c.Request.Context(), pointling); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if !bindPathID(c, "pointling_id", &pointling.PointlingID) || !h.authorizePointling(c, pointling.PointlingID) {
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "updateNickname"})
+	updated, err := h.service.UpdateNickname(c.Request.Context(), pointling)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
 }
 
 func (h *PointlingHandler) ListUserPointlings(c *gin.Context) {
-	userID := strings.TrimSpace(strings.ToUpper(strings.TrimPrefix(c.Param("user_id"), "/")))
+	userID := strings.TrimSpace(strings.TrimPrefix(c.Param("user_id"), "/"))
+	if !authorizeUser(c, userID) {
+		return
+	}
 	pointlings, err := h.service.ListUserPointlings(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		fail(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, pointlings)
@@ -144,17 +223,17 @@ func (h *PointlingHandler) ListUserPointlings(c *gin.Context) {
 func (h *PointlingHandler) ListItems(c *gin.Context) {
 	items, err := h.service.ListItems(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		fail(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, items)
 }
 
 func (h *PointlingHandler) GetItem(c *gin.Context) {
-	itemID := strings.TrimSpace(strings.ToUpper(strings.TrimPrefix(c.Param("item_id"), "/")))
+	itemID := strings.TrimSpace(strings.TrimPrefix(c.Param("item_id"), "/"))
 	item, err := h.service.GetItem(c.Request.Context(), itemID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		fail(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, item)
@@ -163,61 +242,64 @@ func (h *PointlingHandler) GetItem(c *gin.Context) {
 func (h *PointlingHandler) CreateItem(c *gin.Context) {
 	var item models.CreateItemRequest
 	if err := c.ShouldBindJSON(&item); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		fail(c, bindingError(err))
 		return
 	}
-	if _, err := h.service.CreateItem(c.Request.Context(), item); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	created, err := h.service.CreateItem(c.Request.Context(), item)
+	if err != nil {
+		fail(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "createItem"})
+	c.Header("Location", fmt.Sprintf("/api/items/%d", created.ItemID))
+	c.JSON(http.StatusCreated, created)
 }
 
 func (h *PointlingHandler) GetInventory(c *gin.Context) {
-	userID := strings.TrimSpace(strings.ToUpper(strings.TrimPrefix(c.Param("user_id"), "/")))
-	inventory, err := h.service.GetInventory(c.Request.Context(), userID)
+	pointlingID := strings.TrimSpace(strings.TrimPrefix(c.Param("pointling_id"), "/"))
+	if !h.authorizePointling(c, pointlingID) {
+		return
+	}
+	inventory, err := h.service.GetInventory(c.Request.Context(), pointlingID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		fail(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, inventory)
 }
 
-func (h *PointlingHandler) AcquireItem(c *gin.Context) {
-	var item models.AcquireItemRequest
-	if err := c.ShouldBindJSON(&item); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+func (h *PointlingHandler) PurchaseItem(c *gin.Context) {
+	var purchase models.PurchaseItemRequest
+	if err := c.ShouldBindJSON(&purchase); err != nil {
+		fail(c, bindingError(err))
 		return
 	}
-	if _, err := h.service.AcquireItem(c.Request.Context(), item); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if !authorizeUser(c, purchase.UserID) {
+		return
+	}
+	purchase.PointlingID = strings.TrimSpace(strings.TrimPrefix(c.Param("pointling_id"), "/"))
+	purchase.ItemID = strings.TrimSpace(strings.TrimPrefix(c.Param("item_id"), "/"))
+	receipt, err := h.service.PurchaseItem(c.Request.Context(), purchase)
+	if err != nil {
+		fail(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "acquireItem"})
+	c.JSON(http.StatusOK, receipt)
 }
 
 func (h *PointlingHandler) ToggleEquipped(c *gin.Context) {
 	var item models.ToggleEquippedRequest
 	if err := c.ShouldBindJSON(&item); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		fail(c, bindingError(err))
 		return
 	}
-	if _, err := h.service.ToggleEquipped(c.Request.Context(), item); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if !bindPathID(c, "pointling_id", &item.PointlingID) || !bindPathID(c, "item_id", &item.ItemID) ||
+		!h.authorizePointling(c, item.PointlingID) {
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "toggleEquipped"})
-}
-
-func (h *PointlingHandler) SpendPoints(c *gin.Context) {
-	var item models.SpendPointsRequest
-	if err := c.ShouldBindJSON(&item); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-	if _, err := h.service.SpendPoints(c.Request.Context(), item); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	updated, err := h.service.ToggleEquipped(c.Request.Context(), item)
+	if err != nil {
+		fail(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "spendPoints"})
+	c.JSON(http.StatusOK, updated)
 }
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"my-pointlings-be/internal/auth"
+	"my-pointlings-be/internal/middleware"
+	"my-pointlings-be/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// pathService records the ID each write handler passed on to the service.
+type pathService struct {
+	ownerService
+	got []string
+}
+
+func (s *pathService) AddXP(c context.Context, req models.AddXPRequest) (models.XPUpdateResponse, error) {
+	s.got = []string{req.PointlingID}
+	return models.XPUpdateResponse{}, nil
+}
+
+func (s *pathService) UpdateNickname(c context.Context, req models.UpdateNicknameRequest) (models.Pointling, error) {
+	s.got = []string{req.PointlingID}
+	return models.Pointling{}, nil
+}
+
+func (s *pathService) ClaimReward(c context.Context, req models.ClaimRewardRequest) (models.LevelReward, error) {
+	s.got = []string{req.PointlingID}
+	return models.LevelReward{}, nil
+}
+
+func (s *pathService) SetColor(c context.Context, req models.SetColorRequest) (models.Pointling, error) {
+	s.got = []string{req.PointlingID}
+	return models.Pointling{}, nil
+}
+
+func (s *pathService) ToggleEquipped(c context.Context, req models.ToggleEquippedRequest) (models.Pointling, error) {
+	s.got = []string{req.PointlingID, req.ItemID}
+	return models.Pointling{}, nil
+}
+
+func (s *pathService) UpdateUserTimezone(c context.Context, req models.UpdateUserTimezoneRequest) (models.User, error) {
+	s.got = []string{req.UserID}
+	return models.User{}, nil
+}
+
+func (s *pathService) UpdateUserPoints(c context.Context, req models.UpdateUserPointsRequest) (models.User, error) {
+	s.got = []string{req.UserID}
+	return models.User{}, nil
+}
+
+func TestWritesActOnPathIDs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	verifier, err := auth.NewVerifier(auth.Config{HS256Secret: testSecret, UserIDClaim: "user_id"})
+	require.NoError(t, err)
+
+	svc := &pathService{ownerService: ownerService{owners: map[string]int64{"10": 1, "20": 2}}}
+	h := New(svc)
+	r := gin.New()
+	r.Use(middleware.Errors())
+	r.Use(middleware.Authenticate(verifier))
+	r.POST("/pointlings/:pointling_id/xp", h.AddXP)
+	r.PATCH("/pointlings/:pointling_id/nickname", h.UpdateNickname)
+	r.POST("/pointlings/:pointling_id/rewards", h.ClaimReward)
+	r.PATCH("/pointlings/:pointling_id/color", h.SetColor)
+	r.PATCH("/pointlings/:pointling_id/items/:item_id/equip", h.ToggleEquipped)
+	r.PATCH("/users/:user_id/timezone", h.UpdateUserTimezone)
+	r.PATCH("/users/:user_id/points", h.UpdateUserPoints)
+
+	player := "Bearer " + mintToken(t, "1")
+	admin := "Bearer " + mintToken(t, "99", auth.RoleAdmin)
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		body    string
+		header  string
+		want    int
+		wantIDs []string
+	}{
+		{"xp from path", http.MethodPost, "/pointlings/10/xp", `{"source": "PLAY"}`, player, http.StatusOK, []string{"10"}},
+		{"xp matching body", http.MethodPost, "/pointlings/10/xp", `{"pointling_id": "10", "source": "PLAY"}`, player, http.StatusOK, []string{"10"}},
+		{"xp other pointling in body", http.MethodPost, "/pointlings/10/xp", `{"pointling_id": "20", "source": "PLAY"}`, player, http.StatusBadRequest, nil},
+		{"nickname other pointling in body", http.MethodPatch, "/pointlings/10/nickname", `{"pointling_id": "20", "nickname": "Nova"}`, player, http.StatusBadRequest, nil},
+		{"claim other pointling in body", http.MethodPost, "/pointlings/10/rewards",
+			`{"pointling_id": "20", "reward_id": "1", "option": {"type": "COLOR", "id": "#FFFFFF"}}`, player, http.StatusBadRequest, nil},
+		{"color other pointling in body", http.MethodPatch, "/pointlings/10/color", `{"pointling_id": "20", "color_hex": "#FFFFFF"}`, player, http.StatusBadRequest, nil},
+		{"equip from path", http.MethodPatch, "/pointlings/10/items/3/equip", `{"equipped": true}`, player, http.StatusOK, []string{"10", "3"}},
+		{"equip other item in body", http.MethodPatch, "/pointlings/10/items/3/equip", `{"item_id": "4", "equipped": true}`, player, http.StatusBadRequest, nil},
+		{"timezone other user in body", http.MethodPatch, "/users/1/timezone", `{"user_id": "2", "timezone": "UTC"}`, player, http.StatusBadRequest, nil},
+		{"timezone path of other user", http.MethodPatch, "/users/2/timezone", `{"timezone": "UTC"}`, player, http.StatusForbidden, nil},
+		{"points from path", http.MethodPatch, "/users/2/points", `{"point_amount": 5, "reason": "ADMIN_GRANT"}`, admin, http.StatusOK, []string{"2"}},
+		{"points other user in body", http.MethodPatch, "/users/2/points", `{"user_id": "3", "point_amount": 5, "reason": "ADMIN_GRANT"}`, admin, http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc.got = nil
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", tt.header)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			require.Equal(t, tt.want, w.Code, w.Body.String())
+			require.Equal(t, tt.wantIDs, svc.got)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	models "my-pointlings-be/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *PointlingHandler) RefundSpend(c *gin.Context) {
+	var refund models.RefundSpendRequest
+	if err := c.ShouldBindJSON(&refund); err != nil {
+		fail(c, bindingError(err))
+		return
+	}
+	refund.SpendID = strings.TrimSpace(strings.TrimPrefix(c.Param("spend_id"), "/"))
+	reversal, err := h.service.RefundSpend(c.Request.Context(), refund)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, reversal)
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	models "my-pointlings-be/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *PointlingHandler) GetPendingRewards(c *gin.Context) {
+	pointlingID := strings.TrimSpace(strings.TrimPrefix(c.Param("pointling_id"), "/"))
+	if !h.authorizePointling(c, pointlingID) {
+		return
+	}
+	rewards, err := h.service.GetPendingRewards(c.Request.Context(), pointlingID)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rewards)
+}
+
+func (h *PointlingHandler) ClaimReward(c *gin.Context) {
+	var claim models.ClaimRewardRequest
+	if err := c.ShouldBindJSON(&claim); err != nil {
+		fail(c, bindingError(err))
+		return
+	}
+	if !bindPathID(c, "pointling_id", &claim.PointlingID) || !h.authorizePointling(c, claim.PointlingID) {
+		return
+	}
+	reward, err := h.service.ClaimReward(c.Request.Context(), claim)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, reward)
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	models "my-pointlings-be/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+var spendHistoryCSVHeader = []string{
+	"spend_id", "spend_ts", "item_id", "item_name", "category", "rarity", "points_spent", "reversed_at",
+}
+
+func (h *PointlingHandler) GetSpendHistory(c *gin.Context) {
+	var query models.SpendHistoryRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		fail(c, bindingError(err))
+		return
+	}
+	query.UserID = strings.TrimSpace(strings.TrimPrefix(c.Param("user_id"), "/"))
+	if !authorizeUser(c, query.UserID) {
+		return
+	}
+
+	switch query.Format {
+	case "":
+		history, err := h.service.GetSpendHistory(c.Request.Context(), query)
+		if err != nil {
+			fail(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, history)
+	case models.SpendHistoryFormatCSV:
+		spends, err := h.service.ExportSpendHistory(c.Request.Context(), query)
+		if err != nil {
+			fail(c, err)
+			return
+		}
+		writeSpendHistoryCSV(c, query.UserID, spends)
+	default:
+		fail(c, fmt.Errorf("%w: unknown format %q", models.ErrInvalidSpendHistoryQuery, query.Format))
+	}
+}
+
+func writeSpendHistoryCSV(c *gin.Context, userID string, spends []models.PointSpend) {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="points-history-%s.csv"`, userID))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write(spendHistoryCSVHeader)
+	for _, s := range spends {
+		row := []string{
+			strconv.FormatInt(s.SpendID, 10),
+			s.SpendTS.UTC().Format(time.RFC3339),
+			strconv.FormatInt(s.ItemID, 10),
+			"", "", "",
+			strconv.Itoa(s.PointsSpent),
+			"",
+		}
+		if s.ReversedAt != nil {
+			row[7] = s.ReversedAt.UTC().Format(time.RFC3339)
+		}
+		if s.Item != nil {
+			row[3], row[4], row[5] = s.Item.Name, string(s.Item.Category), string(s.Item.Rarity)
+		}
+		_ = w.Write(row)
+	}
+	w.Flush()
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"my-pointlings-be/internal/models"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// Report binding failures under the names clients send, not Go field names.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(f reflect.StructField) string {
+			for _, tag := range []string{"json", "form"} {
+				name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+				if name != "" && name != "-" {
+					return name
+				}
+			}
+			return f.Name
+		})
+	}
+}
+
+// bindingError turns a failed ShouldBind call into a validation error that
+// lists each rejected field.
+func bindingError(err error) error {
+	var fe models.FieldErrors
+	var invalid validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &invalid):
+		for _, e := range invalid {
+			fe.Add(e.Field(), validationMessage(e))
+		}
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		fe.Add(typeErr.Field, "must be a "+typeErr.Type.String())
+	default:
+		return models.NewValidationError(err)
+	}
+	return fe.Err()
+}
+
+func validationMessage(e validator.FieldError) string {
+	switch e.Tag() {
+	case "required":
+		return "is required"
+	case "min", "gte":
+		return "must be at least " + e.Param()
+	case "max", "lte":
+		return "must be at most " + e.Param()
+	case "gt":
+		return "must be greater than " + e.Param()
+	case "oneof":
+		return "must be one of " + e.Param()
+	default:
+		return "failed the " + e.Tag() + " check"
+	}
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+
+	models "my-pointlings-be/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *PointlingHandler) GetXPConfig(c *gin.Context) {
+	cfg, err := h.service.GetXPConfig(c.Request.Context())
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cfg)
+}
+
+func (h *PointlingHandler) ListXPConfigVersions(c *gin.Context) {
+	versions, err := h.service.ListXPConfigVersions(c.Request.Context())
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, versions)
+}
+
+func (h *PointlingHandler) UpdateXPConfig(c *gin.Context) {
+	var update models.UpdateXPConfigRequest
+	if err := c.ShouldBindJSON(&update); err != nil {
+		fail(c, bindingError(err))
+		return
+	}
+	cfg, err := h.service.UpdateXPConfig(c.Request.Context(), update)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cfg)
+}
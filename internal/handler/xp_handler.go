@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	models "my-pointlings-be/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *PointlingHandler) GetXPHistory(c *gin.Context) {
+	var query models.XPHistoryRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		fail(c, bindingError(err))
+		return
+	}
+	query.PointlingID = strings.TrimSpace(strings.TrimPrefix(c.Param("pointling_id"), "/"))
+	if !h.authorizePointling(c, query.PointlingID) {
+		return
+	}
+	history, err := h.service.GetXPHistory(c.Request.Context(), query)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
+
+func (h *PointlingHandler) GetXPLimits(c *gin.Context) {
+	pointlingID := strings.TrimSpace(strings.TrimPrefix(c.Param("pointling_id"), "/"))
+	if !h.authorizePointling(c, pointlingID) {
+		return
+	}
+	limits, err := h.service.GetXPLimits(c.Request.Context(), pointlingID)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, limits)
+}
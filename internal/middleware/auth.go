@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"my-pointlings-be/internal/auth"
+	"my-pointlings-be/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// PrincipalKey is the gin context key holding the authenticated auth.Principal.
+	PrincipalKey = "principal"
+
+	// APIKeyHeader carries an admin API key in place of a bearer token.
+	APIKeyHeader = "X-API-Key"
+)
+
+// Authenticate requires a valid bearer token on every request and stores the
+// caller in both the gin context and the request context.
+func Authenticate(verifier *auth.Verifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := verifyBearer(c, verifier)
+		if !ok {
+			return
+		}
+		setPrincipal(c, principal)
+		c.Next()
+	}
+}
+
+// RequireAdmin admits callers presenting a configured API key, or a bearer
+// token whose roles include auth.RoleAdmin.
+func RequireAdmin(verifier *auth.Verifier, keys auth.APIKeys) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if presented := c.GetHeader(APIKeyHeader); presented != "" {
+			principal, ok := keys.Authenticate(presented)
+			if !ok {
+				abort(c, models.NewUnauthorizedError(errors.New("invalid api key")))
+				return
+			}
+			setPrincipal(c, principal)
+			c.Next()
+			return
+		}
+
+		principal, ok := verifyBearer(c, verifier)
+		if !ok {
+			return
+		}
+		if !principal.HasRole(auth.RoleAdmin) {
+			abort(c, fmt.Errorf("%w: admin role required", models.ErrForbidden))
+			return
+		}
+		setPrincipal(c, principal)
+		c.Next()
+	}
+}
+
+// verifyBearer checks the request's bearer token, answering with 401 when it
+// is missing or invalid.
+func verifyBearer(c *gin.Context, verifier *auth.Verifier) (auth.Principal, bool) {
+	token, ok := bearerToken(c.GetHeader("Authorization"))
+	if !ok {
+		c.Header("WWW-Authenticate", "Bearer")
+		abort(c, fmt.Errorf("%w: missing bearer token", models.ErrUnauthenticated))
+		return auth.Principal{}, false
+	}
+	principal, err := verifier.Verify(token)
+	if err != nil {
+		c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+		abort(c, models.NewUnauthorizedError(err))
+		return auth.Principal{}, false
+	}
+	return principal, true
+}
+
+func setPrincipal(c *gin.Context, principal auth.Principal) {
+	c.Set(PrincipalKey, principal)
+	c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+}
+
+// abort records err for the Errors middleware and stops the chain.
+func abort(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"my-pointlings-be/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details.
+const ProblemContentType = "application/problem+json"
+
+var kindStatus = map[models.ErrorKind]int{
+	models.KindNotFound:     http.StatusNotFound,
+	models.KindConflict:     http.StatusConflict,
+	models.KindValidation:   http.StatusBadRequest,
+	models.KindForbidden:    http.StatusForbidden,
+	models.KindUnauthorized: http.StatusUnauthorized,
+	models.KindLimitReached: http.StatusTooManyRequests,
+}
+
+// Errors answers requests whose handlers recorded an error with c.Error and
+// wrote nothing, translating the last error into a problem+json response.
+// Register it before every other middleware so it sees their errors too.
+func Errors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		writeProblem(c)
+	}
+}
+
+// writeProblem renders the last recorded error, if there is one and nothing
+// has been written yet. Middleware that needs to see the final response,
+// such as Idempotency, calls it before inspecting what was written.
+func writeProblem(c *gin.Context) {
+	if len(c.Errors) == 0 || c.Writer.Written() {
+		return
+	}
+	err := c.Errors.Last().Err
+
+	status := http.StatusInternalServerError
+	kind, ok := models.KindOf(err)
+	switch {
+	case ok:
+		status = kindStatus[kind]
+	case errors.Is(err, context.DeadlineExceeded):
+		// The request ran out of time, most likely waiting on the database.
+		status = http.StatusServiceUnavailable
+	}
+	problem := models.Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   err.Error(),
+		Instance: c.Request.URL.Path,
+		Code:     kind,
+	}
+	var fields models.FieldErrors
+	if errors.As(err, &fields) {
+		problem.Errors = fields
+	}
+	if !ok {
+		// Unclassified errors are bugs or infrastructure failures; keep their
+		// details in the log rather than the response.
+		log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+		problem.Detail = ""
+	}
+
+	c.Header("Content-Type", ProblemContentType)
+	c.JSON(status, problem)
+}
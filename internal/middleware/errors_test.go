@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"my-pointlings-be/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// serveError runs a handler that records err through the Errors middleware.
+func serveError(err error) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Errors())
+	r.GET("/things/:id", func(c *gin.Context) {
+		_ = c.Error(err)
+	})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/things/7", nil))
+	return w
+}
+
+func decodeProblem(t *testing.T, w *httptest.ResponseRecorder) models.Problem {
+	t.Helper()
+	require.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+	var problem models.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	return problem
+}
+
+func TestErrorsMapsKindsToStatus(t *testing.T) {
+	cause := errors.New("thing 7")
+	tests := []struct {
+		err    error
+		status int
+		kind   models.ErrorKind
+	}{
+		{models.NewNotFoundError(cause), http.StatusNotFound, models.KindNotFound},
+		{models.NewConflictError(cause), http.StatusConflict, models.KindConflict},
+		{models.NewValidationError(cause), http.StatusBadRequest, models.KindValidation},
+		{models.NewForbiddenError(cause), http.StatusForbidden, models.KindForbidden},
+		{models.NewUnauthorizedError(cause), http.StatusUnauthorized, models.KindUnauthorized},
+		{models.NewLimitReachedError(cause), http.StatusTooManyRequests, models.KindLimitReached},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.kind), func(t *testing.T) {
+			// Wrapping, as services and repositories do, keeps the kind.
+			w := serveError(fmt.Errorf("load thing: %w", tt.err))
+			require.Equal(t, tt.status, w.Code)
+
+			problem := decodeProblem(t, w)
+			require.Equal(t, models.Problem{
+				Type:     "about:blank",
+				Title:    http.StatusText(tt.status),
+				Status:   tt.status,
+				Detail:   "load thing: thing 7",
+				Instance: "/things/7",
+				Code:     tt.kind,
+			}, problem)
+		})
+	}
+}
+
+func TestErrorsDeadlineExceeded(t *testing.T) {
+	w := serveError(fmt.Errorf("get user: %w", context.DeadlineExceeded))
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	problem := decodeProblem(t, w)
+	require.Equal(t, http.StatusServiceUnavailable, problem.Status)
+	require.Empty(t, problem.Detail)
+	require.Empty(t, problem.Code)
+}
+
+func TestErrorsIncludesFieldErrors(t *testing.T) {
+	var fe models.FieldErrors
+	fe.Add("pointling_id", "must be a positive integer")
+	fe.Add("item_id", "must be a positive integer")
+
+	w := serveError(fe.Err())
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	problem := decodeProblem(t, w)
+	require.Equal(t, models.KindValidation, problem.Code)
+	require.Equal(t, fe, problem.Errors)
+}
+
+func TestErrorsHidesUnclassifiedDetail(t *testing.T) {
+	w := serveError(errors.New("pq: password authentication failed for user admin"))
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+
+	problem := decodeProblem(t, w)
+	require.Equal(t, models.Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(http.StatusInternalServerError),
+		Status:   http.StatusInternalServerError,
+		Instance: "/things/7",
+	}, problem)
+	require.NotContains(t, w.Body.String(), "password")
+}
+
+func TestErrorsLeavesWrittenResponses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Errors())
+	r.GET("/ok", func(c *gin.Context) {
+		c.String(http.StatusAccepted, "queued")
+		_ = c.Error(errors.New("logged only"))
+	})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ok", nil))
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	require.Equal(t, "queued", w.Body.String())
+}
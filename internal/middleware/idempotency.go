@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"my-pointlings-be/internal/auth"
+	"my-pointlings-be/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IdempotencyStore persists Idempotency-Key reservations and their responses.
+type IdempotencyStore interface {
+	ReserveIdempotencyKey(ctx context.Context, key, requestHash string, ttl time.Duration) (*models.IdempotencyRecord, bool, error)
+	SaveIdempotentResponse(ctx context.Context, key string, statusCode int, contentType string, body []byte) error
+	ReleaseIdempotencyKey(ctx context.Context, key string) error
+}
+
+// Idempotency makes mutating requests that carry an Idempotency-Key header
+// safe to retry. The first request with a key runs normally and its response
+// is stored for ttl; retries with the same method, path and body get that
+// response replayed, and reuse with a different request is rejected.
+// Responses with a 5xx status are not stored, so the client can try again.
+func Idempotency(store IdempotencyStore, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(models.IdempotencyKeyHeader)
+		if key == "" || !isMutating(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if len(key) > models.MaxIdempotencyKeyLength {
+			abort(c, models.ErrInvalidIdempotencyKey)
+			return
+		}
+
+		// Keys are scoped to the caller so one player cannot replay another's response.
+		if principal, ok := auth.FromContext(c.Request.Context()); ok {
+			key = principal.Actor() + ":" + key
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			abort(c, models.NewValidationError(err))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		record, reserved, err := store.ReserveIdempotencyKey(c.Request.Context(), key, requestHash(c.Request, body), ttl)
+		if err != nil {
+			abort(c, err)
+			return
+		}
+
+		if !reserved {
+			replay(c, record, requestHash(c.Request, body))
+			return
+		}
+
+		w := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		// Render any handler error now so the problem body is what gets stored.
+		writeProblem(c)
+
+		// Settle the key even if the client has gone or the request timed out,
+		// or retries would see it in flight until it expires.
+		ctx := context.WithoutCancel(c.Request.Context())
+		status := w.Status()
+		if status >= http.StatusInternalServerError {
+			err = store.ReleaseIdempotencyKey(ctx, key)
+		} else {
+			err = store.SaveIdempotentResponse(ctx, key, status, w.Header().Get("Content-Type"), w.body.Bytes())
+		}
+		if err != nil {
+			// The response has already gone out; a retry will see the key as in flight until it expires.
+			log.Printf("idempotency key %q: %v", key, err)
+		}
+	}
+}
+
+func replay(c *gin.Context, record *models.IdempotencyRecord, hash string) {
+	if record.RequestHash != hash {
+		abort(c, models.ErrIdempotencyKeyMismatch)
+		return
+	}
+	if record.StatusCode == nil {
+		abort(c, models.ErrIdempotencyKeyInFlight)
+		return
+	}
+
+	c.Header(models.IdempotentReplayedHeader, "true")
+	contentType := record.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.Data(*record.StatusCode, contentType, record.ResponseBody)
+	c.Abort()
+}
+
+// requestHash fingerprints what a retry must repeat exactly: the method,
+// the path with its parameters and the body.
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.RequestURI()))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// recordingWriter keeps a copy of the response body as it is written.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
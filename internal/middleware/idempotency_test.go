@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"my-pointlings-be/internal/auth"
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository/memory"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// idempotencyServer routes through Idempotency backed by the in-memory
+// store. The X-Test-User header stands in for authentication.
+type idempotencyServer struct {
+	router  *gin.Engine
+	store   *memory.Repository
+	calls   atomic.Int32
+	entered chan struct{}
+	release chan struct{}
+}
+
+func newIdempotencyServer() *idempotencyServer {
+	gin.SetMode(gin.TestMode)
+	s := &idempotencyServer{
+		router:  gin.New(),
+		store:   memory.New(),
+		entered: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	s.router.Use(Errors())
+	s.router.Use(func(c *gin.Context) {
+		if user := c.GetHeader("X-Test-User"); user != "" {
+			setPrincipal(c, auth.Principal{Subject: user})
+		}
+	})
+	s.router.Use(Idempotency(s.store, time.Hour))
+	s.router.POST("/count", func(c *gin.Context) {
+		n := s.calls.Add(1)
+		c.JSON(http.StatusCreated, gin.H{"calls": n})
+	})
+	s.router.POST("/fail", func(c *gin.Context) {
+		s.calls.Add(1)
+		_ = c.Error(errors.New("database went away"))
+	})
+	s.router.POST("/slow", func(c *gin.Context) {
+		s.calls.Add(1)
+		close(s.entered)
+		<-s.release
+		c.Status(http.StatusNoContent)
+	})
+	return s
+}
+
+func (s *idempotencyServer) do(path, user, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if user != "" {
+		req.Header.Set("X-Test-User", user)
+	}
+	if key != "" {
+		req.Header.Set(models.IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyStoresAndReplays(t *testing.T) {
+	s := newIdempotencyServer()
+
+	first := s.do("/count", "1", "key-1", `{"a":1}`)
+	require.Equal(t, http.StatusCreated, first.Code)
+	require.Empty(t, first.Header().Get(models.IdempotentReplayedHeader))
+
+	record, err := s.store.GetIdempotencyKey(context.Background(), "user:1:key-1")
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	require.Equal(t, http.StatusCreated, *record.StatusCode)
+	require.JSONEq(t, first.Body.String(), string(record.ResponseBody))
+
+	retry := s.do("/count", "1", "key-1", `{"a":1}`)
+	require.Equal(t, http.StatusCreated, retry.Code)
+	require.Equal(t, "true", retry.Header().Get(models.IdempotentReplayedHeader))
+	require.Equal(t, first.Body.String(), retry.Body.String())
+	require.Equal(t, first.Header().Get("Content-Type"), retry.Header().Get("Content-Type"))
+	require.EqualValues(t, 1, s.calls.Load())
+}
+
+func TestIdempotencyWithoutKeyAlwaysRuns(t *testing.T) {
+	s := newIdempotencyServer()
+
+	require.Equal(t, http.StatusCreated, s.do("/count", "1", "", `{}`).Code)
+	require.Equal(t, http.StatusCreated, s.do("/count", "1", "", `{}`).Code)
+	require.EqualValues(t, 2, s.calls.Load())
+}
+
+func TestIdempotencyRejectsReuseWithDifferentRequest(t *testing.T) {
+	s := newIdempotencyServer()
+	require.Equal(t, http.StatusCreated, s.do("/count", "1", "key-1", `{"a":1}`).Code)
+
+	tests := []struct {
+		name, path, body string
+	}{
+		{"Body", "/count", `{"a":2}`},
+		{"Path", "/count?x=1", `{"a":1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := s.do(tt.path, "1", "key-1", tt.body)
+			require.Equal(t, http.StatusConflict, w.Code)
+			require.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+			require.Contains(t, w.Body.String(), models.ErrIdempotencyKeyMismatch.Error())
+		})
+	}
+	require.EqualValues(t, 1, s.calls.Load())
+}
+
+func TestIdempotencyRejectsRetryWhileInFlight(t *testing.T) {
+	s := newIdempotencyServer()
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- s.do("/slow", "1", "key-1", `{}`) }()
+	<-s.entered
+
+	w := s.do("/slow", "1", "key-1", `{}`)
+	require.Equal(t, http.StatusConflict, w.Code)
+	require.Contains(t, w.Body.String(), models.ErrIdempotencyKeyInFlight.Error())
+
+	close(s.release)
+	require.Equal(t, http.StatusNoContent, (<-done).Code)
+	require.EqualValues(t, 1, s.calls.Load())
+}
+
+func TestIdempotencyReleasesKeyAfterServerError(t *testing.T) {
+	s := newIdempotencyServer()
+
+	w := s.do("/fail", "1", "key-1", `{}`)
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	record, err := s.store.GetIdempotencyKey(context.Background(), "user:1:key-1")
+	require.NoError(t, err)
+	require.Nil(t, record)
+
+	w = s.do("/fail", "1", "key-1", `{}`)
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	require.Empty(t, w.Header().Get(models.IdempotentReplayedHeader))
+	require.EqualValues(t, 2, s.calls.Load())
+}
+
+func TestIdempotencyKeysAreScopedToTheCaller(t *testing.T) {
+	s := newIdempotencyServer()
+
+	for i, user := range []string{"1", "2"} {
+		w := s.do("/count", user, "shared-key", `{}`)
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.Empty(t, w.Header().Get(models.IdempotentReplayedHeader))
+		require.JSONEq(t, `{"calls":`+strconv.Itoa(i+1)+`}`, w.Body.String())
+	}
+
+	w := s.do("/count", "1", "shared-key", `{}`)
+	require.Equal(t, "true", w.Header().Get(models.IdempotentReplayedHeader))
+	require.JSONEq(t, `{"calls":1}`, w.Body.String())
+}
+
+func TestIdempotencyRejectsOverlongKey(t *testing.T) {
+	s := newIdempotencyServer()
+
+	w := s.do("/count", "1", strings.Repeat("k", models.MaxIdempotencyKeyLength+1), `{}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Zero(t, s.calls.Load())
+}
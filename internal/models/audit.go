@@ -0,0 +1,71 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Admin Audit Models
+
+const (
+	AuditActionItemCreate     = "item.create"
+	AuditActionPointsAdjust   = "user.points.adjust"
+	AuditActionXPConfigUpdate = "xp_config.update"
+	AuditActionLedgerBackfill = "ledger.backfill"
+	AuditActionSpendRefund    = "point_spend.refund"
+	DefaultAuditLogLimit      = 50
+	MaxAuditLogLimit          = 200
+)
+
+// AuditEntry records one admin change with snapshots of the target before
+// and after it. Before is null for creations.
+type AuditEntry struct {
+	AuditID    int64           `json:"audit_id" db:"audit_id"`
+	Actor      string          `json:"actor" db:"actor"`
+	Action     string          `json:"action" db:"action"`
+	TargetType string          `json:"target_type" db:"target_type"`
+	TargetID   string          `json:"target_id" db:"target_id"`
+	Before     json.RawMessage `json:"before" db:"before"`
+	After      json.RawMessage `json:"after" db:"after"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+}
+
+type AuditLogRequest struct {
+	Before     string `form:"before"`
+	Limit      int    `form:"limit"`
+	TargetType string `form:"target_type"`
+	TargetID   string `form:"target_id"`
+}
+
+type AuditLogFilter struct {
+	BeforeID   int64
+	Limit      int
+	TargetType string
+	TargetID   string
+}
+
+type AuditLogResponse struct {
+	Entries    []AuditEntry `json:"entries"`
+	NextBefore string       `json:"next_before,omitempty"`
+}
+
+// NewAuditEntry snapshots before and after as JSON.
+func NewAuditEntry(actor, action, targetType, targetID string, before, after interface{}) (*AuditEntry, error) {
+	beforeJSON, err := json.Marshal(before)
+	if err != nil {
+		return nil, fmt.Errorf("marshal audit before: %w", err)
+	}
+	afterJSON, err := json.Marshal(after)
+	if err != nil {
+		return nil, fmt.Errorf("marshal audit after: %w", err)
+	}
+	return &AuditEntry{
+		Actor:      actor,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     beforeJSON,
+		After:      afterJSON,
+	}, nil
+}
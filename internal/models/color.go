@@ -0,0 +1,68 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Color Models
+
+// ColorUnlockInterval is how many levels apart milestone colors unlock.
+const ColorUnlockInterval = 5
+
+var ErrColorNotOwned = NewConflictError(errors.New("color not owned by pointling"))
+
+// ColorPalette lists every color a pointling can own, in unlock order.
+var ColorPalette = []string{
+	"#F6C945", // sunshine
+	"#FF8FA3", // blush
+	"#7BD389", // mint
+	"#6EC6FF", // sky
+	"#B28DFF", // lavender
+	"#FF9F45", // tangerine
+	"#4ECDC4", // teal
+	"#F25F5C", // cherry
+	"#A0A4B8", // storm
+	"#2E294E", // midnight
+}
+
+type PointlingColor struct {
+	PointlingID int64     `json:"pointling_id" db:"pointling_id"`
+	ColorHex    string    `json:"color_hex" db:"color_hex"`
+	AcquiredAt  time.Time `json:"acquired_at" db:"acquired_at"`
+}
+
+type SetColorRequest struct {
+	PointlingID string `json:"pointling_id"`
+	ColorHex    string `json:"color_hex" binding:"required"`
+}
+
+type ColorListResponse struct {
+	ActiveColor string           `json:"active_color,omitempty"`
+	Colors      []PointlingColor `json:"colors"`
+}
+
+// DefaultColor is granted to every new pointling.
+func DefaultColor() string {
+	return ColorPalette[0]
+}
+
+// IsColorMilestone reports whether reaching level unlocks a palette color.
+func IsColorMilestone(level int) bool {
+	return level > 0 && level%ColorUnlockInterval == 0
+}
+
+// NextPaletteColor returns the first palette color not in owned, or "" once
+// the whole palette is unlocked.
+func NextPaletteColor(owned []*PointlingColor) string {
+	have := make(map[string]bool, len(owned))
+	for _, c := range owned {
+		have[c.ColorHex] = true
+	}
+	for _, hex := range ColorPalette {
+		if !have[hex] {
+			return hex
+		}
+	}
+	return ""
+}
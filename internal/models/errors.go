@@ -0,0 +1,80 @@
+package models
+
+import "errors"
+
+// ErrorKind classifies a failure by how the caller should react to it.
+type ErrorKind string
+
+const (
+	KindNotFound     ErrorKind = "not_found"
+	KindConflict     ErrorKind = "conflict"
+	KindValidation   ErrorKind = "validation"
+	KindForbidden    ErrorKind = "forbidden"
+	KindUnauthorized ErrorKind = "unauthorized"
+	KindLimitReached ErrorKind = "limit_reached"
+)
+
+// DomainError wraps an error with its kind. Sentinel errors are declared as
+// DomainErrors, so anything wrapping them with fmt.Errorf("%w") keeps its
+// kind, and errors.Is against the sentinel keeps working.
+type DomainError struct {
+	Kind ErrorKind
+	Err  error
+}
+
+func (e *DomainError) Error() string { return e.Err.Error() }
+
+func (e *DomainError) Unwrap() error { return e.Err }
+
+func NewNotFoundError(err error) *DomainError {
+	return &DomainError{Kind: KindNotFound, Err: err}
+}
+
+func NewConflictError(err error) *DomainError {
+	return &DomainError{Kind: KindConflict, Err: err}
+}
+
+func NewValidationError(err error) *DomainError {
+	return &DomainError{Kind: KindValidation, Err: err}
+}
+
+func NewForbiddenError(err error) *DomainError {
+	return &DomainError{Kind: KindForbidden, Err: err}
+}
+
+func NewUnauthorizedError(err error) *DomainError {
+	return &DomainError{Kind: KindUnauthorized, Err: err}
+}
+
+func NewLimitReachedError(err error) *DomainError {
+	return &DomainError{Kind: KindLimitReached, Err: err}
+}
+
+// KindOf returns the kind of the outermost DomainError in err's chain.
+func KindOf(err error) (ErrorKind, bool) {
+	var de *DomainError
+	if errors.As(err, &de) {
+		return de.Kind, true
+	}
+	return "", false
+}
+
+var (
+	ErrUserNotFound      = NewNotFoundError(errors.New("user not found"))
+	ErrPointlingNotFound = NewNotFoundError(errors.New("pointling not found"))
+	ErrItemNotOwned      = NewNotFoundError(errors.New("pointling does not own this item"))
+	ErrForbidden         = NewForbiddenError(errors.New("caller may not access this resource"))
+	ErrUnauthenticated   = NewUnauthorizedError(errors.New("authentication required"))
+)
+
+// Problem is an RFC 7807 problem details body.
+type Problem struct {
+	Type     string    `json:"type"`
+	Title    string    `json:"title"`
+	Status   int       `json:"status"`
+	Detail   string    `json:"detail,omitempty"`
+	Instance string    `json:"instance,omitempty"`
+	Code     ErrorKind `json:"code,omitempty"`
+	// Errors lists the rejected fields of a validation problem.
+	Errors FieldErrors `json:"errors,omitempty"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Idempotency Models
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	MaxIdempotencyKeyLength  = 255
+	IdempotencyPurgeInterval = time.Hour
+)
+
+var (
+	ErrIdempotencyKeyMismatch = NewConflictError(errors.New("idempotency key was already used with a different request"))
+	ErrIdempotencyKeyInFlight = NewConflictError(errors.New("a request with this idempotency key is still being processed"))
+	ErrInvalidIdempotencyKey  = NewValidationError(errors.New("invalid idempotency key"))
+)
+
+// IdempotencyRecord remembers the first request made with a key and, once it
+// finished, the response to replay for retries. StatusCode is nil while the
+// first request is still running.
+type IdempotencyRecord struct {
+	Key          string    `json:"key" db:"idem_key"`
+	RequestHash  string    `json:"request_hash" db:"request_hash"`
+	StatusCode   *int      `json:"status_code,omitempty" db:"status_code"`
+	ContentType  string    `json:"content_type" db:"content_type"`
+	ResponseBody []byte    `json:"-" db:"response_body"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	ExpiresAt    time.Time `json:"expires_at" db:"expires_at"`
+}
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // Item Models
 
@@ -25,6 +28,36 @@ const (
 	RarityLegendary ItemRarity = "LEGENDARY"
 )
 
+// Valid reports whether s is one of the known equipment slots.
+func (s ItemSlot) Valid() bool {
+	switch s {
+	case SlotHat, SlotShoes, SlotFace, SlotWings:
+		return true
+	default:
+		return false
+	}
+}
+
+// Valid reports whether c is one of the known item categories.
+func (c ItemCategory) Valid() bool {
+	switch c {
+	case CategoryAccessory, CategoryFeature:
+		return true
+	default:
+		return false
+	}
+}
+
+// Valid reports whether r is one of the known rarities.
+func (r ItemRarity) Valid() bool {
+	switch r {
+	case RarityCommon, RarityRare, RarityEpic, RarityLegendary:
+		return true
+	default:
+		return false
+	}
+}
+
 type Item struct {
 	ItemID      int64        `json:"item_id" db:"item_id"`
 	Category    ItemCategory `json:"category" db:"category"`
@@ -45,21 +78,43 @@ type PointlingItem struct {
 }
 
 type CreateItemRequest struct {
-	Name     string `json:"name" binding:"required"`
-	Cost     int    `json:"cost" binding:"required"`
-	Category string `json:"category" binding:"required"`
-	Rarity   string `json:"rarity"`
-	AssetID  string `json:"asset_id" binding:"required"`
+	Name     string       `json:"name" binding:"required"`
+	Cost     int          `json:"cost" binding:"required"`
+	Category ItemCategory `json:"category" binding:"required"`
+	// Slot is required for accessories and must be empty for features.
+	Slot    ItemSlot   `json:"slot"`
+	Rarity  ItemRarity `json:"rarity"`
+	AssetID string     `json:"asset_id" binding:"required"`
 }
 
-type AcquireItemRequest struct {
-	PointlingID string `json:"pointling_id" binding:"required"`
-	ItemID      string `json:"item_id" binding:"required"`
+// Validate checks the request against the item enums. An empty rarity is
+// left for the caller to default.
+func (r CreateItemRequest) Validate() error {
+	var fe FieldErrors
+	if r.Cost < 0 {
+		fe.Add("cost", "must not be negative")
+	}
+	switch r.Category {
+	case CategoryAccessory:
+		if !r.Slot.Valid() {
+			fe.Add("slot", fmt.Sprintf("must be one of %s, %s, %s, %s for accessories", SlotHat, SlotShoes, SlotFace, SlotWings))
+		}
+	case CategoryFeature:
+		if r.Slot != "" {
+			fe.Add("slot", "must be empty for features")
+		}
+	default:
+		fe.Add("category", fmt.Sprintf("must be one of %s, %s", CategoryAccessory, CategoryFeature))
+	}
+	if r.Rarity != "" && !r.Rarity.Valid() {
+		fe.Add("rarity", fmt.Sprintf("must be one of %s, %s, %s, %s", RarityCommon, RarityRare, RarityEpic, RarityLegendary))
+	}
+	return fe.Err()
 }
 
 type ToggleEquippedRequest struct {
-	PointlingID string `json:"pointling_id" binding:"required"`
-	ItemID      string `json:"item_id" binding:"required"`
+	PointlingID string `json:"pointling_id"`
+	ItemID      string `json:"item_id"`
 	Equipped    bool   `json:"equipped" binding:"required"`
 }
 
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Points Ledger Models
+
+type LedgerReason string
+
+const (
+	LedgerReasonOpeningBalance  LedgerReason = "OPENING_BALANCE"
+	LedgerReasonReceipt         LedgerReason = "RECEIPT"
+	LedgerReasonPromo           LedgerReason = "PROMO"
+	LedgerReasonRefund          LedgerReason = "REFUND"
+	LedgerReasonAdminGrant      LedgerReason = "ADMIN_GRANT"
+	LedgerReasonAdminAdjustment LedgerReason = "ADMIN_ADJUSTMENT"
+	LedgerReasonSpend           LedgerReason = "SPEND"
+
+	DefaultLedgerLimit = 50
+	MaxLedgerLimit     = 200
+)
+
+var (
+	ErrInvalidLedgerEntry   = NewValidationError(errors.New("invalid ledger entry"))
+	ErrDuplicateLedgerEntry = NewConflictError(errors.New("ledger entry already recorded for this reference"))
+)
+
+// LedgerEntry is one balance change on a user's points account. The user
+// account moves by Delta and CounterAccount moves by -Delta, so every entry
+// balances; BalanceAfter is the user's running balance once it applied.
+type LedgerEntry struct {
+	EntryID        int64        `json:"entry_id" db:"entry_id"`
+	UserID         int64        `json:"user_id" db:"user_id"`
+	Delta          int64        `json:"delta" db:"delta"`
+	BalanceAfter   int64        `json:"balance_after" db:"balance_after"`
+	Reason         LedgerReason `json:"reason" db:"reason"`
+	CounterAccount string       `json:"counter_account" db:"counter_account"`
+	Reference      *string      `json:"reference,omitempty" db:"reference"`
+	CreatedAt      time.Time    `json:"created_at" db:"created_at"`
+}
+
+type LedgerRequest struct {
+	UserID string `form:"-"`
+	Before string `form:"before"`
+	Limit  int    `form:"limit"`
+}
+
+type LedgerResponse struct {
+	Entries    []LedgerEntry `json:"entries"`
+	NextBefore string        `json:"next_before,omitempty"`
+}
+
+type LedgerReconciliation struct {
+	UserID        int64 `json:"user_id"`
+	StoredBalance int64 `json:"stored_balance"`
+	LedgerBalance int64 `json:"ledger_balance"`
+	Difference    int64 `json:"difference"`
+	Consistent    bool  `json:"consistent"`
+}
+
+type LedgerBackfillResponse struct {
+	UsersBackfilled int64 `json:"users_backfilled"`
+}
+
+// CounterAccount names the system account on the other side of an entry.
+func (r LedgerReason) CounterAccount() string {
+	switch r {
+	case LedgerReasonOpeningBalance:
+		return "OPENING"
+	case LedgerReasonReceipt:
+		return "RECEIPTS"
+	case LedgerReasonPromo:
+		return "PROMOTIONS"
+	case LedgerReasonRefund:
+		return "REFUNDS"
+	case LedgerReasonAdminGrant, LedgerReasonAdminAdjustment:
+		return "ADMIN"
+	case LedgerReasonSpend:
+		return "SHOP"
+	default:
+		return ""
+	}
+}
+
+// CheckDelta reports whether delta has a sign the reason allows: spends only
+// debit, adjustments go either way and everything else only credits.
+func (r LedgerReason) CheckDelta(delta int64) error {
+	if r.CounterAccount() == "" {
+		return fmt.Errorf("%w: unknown reason %q", ErrInvalidLedgerEntry, r)
+	}
+	switch {
+	case delta == 0:
+		return fmt.Errorf("%w: delta must not be zero", ErrInvalidLedgerEntry)
+	case r == LedgerReasonSpend && delta > 0:
+		return fmt.Errorf("%w: %s must be a debit", ErrInvalidLedgerEntry, r)
+	case r != LedgerReasonSpend && r != LedgerReasonAdminAdjustment && delta < 0:
+		return fmt.Errorf("%w: %s must be a credit", ErrInvalidLedgerEntry, r)
+	}
+	return nil
+}
+
+// SpendReference links a ledger entry to the point_spend row it paid for.
+func SpendReference(spendID int64) string {
+	return "point_spend:" + strconv.FormatInt(spendID, 10)
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Look Models
+
+// DefaultBaseBody is the body every pointling starts with.
+const DefaultBaseBody = "classic"
+
+var ErrInvalidLook = NewValidationError(errors.New("invalid look"))
+
+var (
+	baseBodyPattern = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+	colorHexPattern = regexp.MustCompile(`^#[0-9A-F]{6}$`)
+)
+
+// Look is the stored composition of a pointling's appearance: a base body,
+// the active color, the asset worn in each slot and any unlocked features.
+type Look struct {
+	BaseBody string              `json:"base_body"`
+	Color    string              `json:"color,omitempty"`
+	Slots    map[ItemSlot]string `json:"slots"`
+	Features []string            `json:"features,omitempty"`
+}
+
+// NewLook returns the look of a freshly created pointling.
+func NewLook() Look {
+	return Look{
+		BaseBody: DefaultBaseBody,
+		Color:    DefaultColor(),
+		Slots:    map[ItemSlot]string{},
+	}
+}
+
+// Scan decodes a stored look_json value, filling defaults for rows written
+// before looks were typed.
+func (l *Look) Scan(value interface{}) error {
+	*l = Look{}
+	switch v := value.(type) {
+	case nil:
+	case []byte:
+		if err := json.Unmarshal(v, l); err != nil {
+			return err
+		}
+	case string:
+		if err := json.Unmarshal([]byte(v), l); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported look_json type %T", value)
+	}
+	l.normalize()
+	return nil
+}
+
+// normalize replaces missing or malformed legacy values with defaults so old
+// free-form rows still decode into something renderable.
+func (l *Look) normalize() {
+	if !baseBodyPattern.MatchString(l.BaseBody) {
+		l.BaseBody = DefaultBaseBody
+	}
+	l.Color = strings.ToUpper(l.Color)
+	if !colorHexPattern.MatchString(l.Color) {
+		l.Color = ""
+	}
+	if l.Slots == nil {
+		l.Slots = map[ItemSlot]string{}
+	}
+}
+
+// Validate checks the look against the render schema before it is stored.
+func (l Look) Validate() error {
+	var problems []string
+	if !baseBodyPattern.MatchString(l.BaseBody) {
+		problems = append(problems, "base_body must be 1-32 lower-case letters, digits, '-' or '_'")
+	}
+	if l.Color != "" && !colorHexPattern.MatchString(l.Color) {
+		problems = append(problems, "color must be a #RRGGBB hex value")
+	}
+	for slot, asset := range l.Slots {
+		if !slot.Valid() {
+			problems = append(problems, fmt.Sprintf("slots: unknown slot %q", slot))
+		}
+		if asset == "" {
+			problems = append(problems, fmt.Sprintf("slots.%s: asset_id is required", slot))
+		}
+	}
+	for i, asset := range l.Features {
+		if asset == "" {
+			problems = append(problems, fmt.Sprintf("features[%d]: asset_id is required", i))
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("%w: %s", ErrInvalidLook, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// BuildLook recomputes the item-driven parts of a look from the pointling's
+// inventory. Base body and color carry over from base; equipped accessories
+// fill their slots and every owned feature is applied, since features are
+// permanent once unlocked.
+func BuildLook(base Look, owned []*PointlingItem) Look {
+	look := Look{
+		BaseBody: base.BaseBody,
+		Color:    base.Color,
+		Slots:    map[ItemSlot]string{},
+	}
+	look.normalize()
+
+	for _, pi := range owned {
+		if pi.Item == nil {
+			continue
+		}
+		switch {
+		case pi.Item.Category == CategoryFeature:
+			look.Features = append(look.Features, pi.Item.AssetID)
+		case pi.Equipped && pi.Item.Slot != nil:
+			look.Slots[*pi.Item.Slot] = pi.Item.AssetID
+		}
+	}
+	sort.Strings(look.Features)
+	return look
+}
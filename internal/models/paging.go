@@ -0,0 +1,88 @@
+package models
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// History Paging Models
+
+// HistoryCursor is a keyset position on (timestamp, id) in a history listed
+// newest first; a page holds the rows strictly older than it.
+type HistoryCursor struct {
+	TS time.Time
+	ID int64
+}
+
+// Encode renders the cursor as an opaque URL-safe token.
+func (c HistoryCursor) Encode() string {
+	raw := strconv.FormatInt(c.TS.UnixNano(), 10) + ":" + strconv.FormatInt(c.ID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeHistoryCursor parses a token produced by HistoryCursor.Encode. A
+// malformed token is reported as invalid, the paged history's query error.
+func DecodeHistoryCursor(token string, invalid error) (*HistoryCursor, error) {
+	malformed := fmt.Errorf("%w: malformed cursor", invalid)
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, malformed
+	}
+	ts, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, malformed
+	}
+	nanos, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return nil, malformed
+	}
+	rowID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, malformed
+	}
+	return &HistoryCursor{TS: time.Unix(0, nanos).UTC(), ID: rowID}, nil
+}
+
+// TimeRange bounds a history to [From, To); a nil end is open.
+type TimeRange struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// ParseTimeRange parses optional RFC3339 from and to query values. Problems
+// are reported as invalid, the history's query error.
+func ParseTimeRange(from, to string, invalid error) (TimeRange, error) {
+	var r TimeRange
+	if from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return r, fmt.Errorf("%w: from must be RFC3339", invalid)
+		}
+		r.From = &t
+	}
+	if to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return r, fmt.Errorf("%w: to must be RFC3339", invalid)
+		}
+		r.To = &t
+	}
+	if r.From != nil && r.To != nil && !r.From.Before(*r.To) {
+		return r, fmt.Errorf("%w: from must be before to", invalid)
+	}
+	return r, nil
+}
+
+// Contains reports whether t falls within the range.
+func (r TimeRange) Contains(t time.Time) bool {
+	if r.From != nil && t.Before(*r.From) {
+		return false
+	}
+	if r.To != nil && !t.Before(*r.To) {
+		return false
+	}
+	return true
+}
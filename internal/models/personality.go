@@ -0,0 +1,94 @@
+package models
+
+import "errors"
+
+// Personality Models
+
+// ExpandPersonality embeds the full personality in pointling responses.
+const ExpandPersonality = "personality"
+
+var (
+	ErrPersonalityNotFound = NewNotFoundError(errors.New("personality not found"))
+	ErrInvalidExpand       = NewValidationError(errors.New("unsupported expand value"))
+)
+
+type Personality struct {
+	PersonalityID    int                   `json:"personality_id" db:"personality_id"`
+	Code             string                `json:"code" db:"code"`
+	Name             string                `json:"name" db:"name"`
+	Traits           []string              `json:"traits" db:"traits"`
+	IdleAnimationIDs []string              `json:"idle_animation_ids" db:"idle_animation_ids"`
+	DialogueTags     []string              `json:"dialogue_tags" db:"dialogue_tags"`
+	Weight           int                   `json:"weight" db:"weight"`
+	XPBonusPercent   map[XPEventSource]int `json:"xp_bonus_percent,omitempty" db:"xp_bonus_percent"`
+}
+
+type PersonalityListResponse struct {
+	Personalities []Personality `json:"personalities"`
+}
+
+// StarterPersonalities returns the catalog that migration
+// 0004_seed_personalities loads, with the IDs a fresh database gives them.
+// The in-memory repository is seeded from it.
+func StarterPersonalities() []Personality {
+	return []Personality{
+		{PersonalityID: 1, Code: "playful", Name: "Playful", Traits: []string{"energetic", "curious"},
+			IdleAnimationIDs: []string{"bounce", "spin"}, DialogueTags: []string{"games", "jokes"}, Weight: 3,
+			XPBonusPercent: map[XPEventSource]int{XPSourcePlay: 20}},
+		{PersonalityID: 2, Code: "thrifty", Name: "Thrifty", Traits: []string{"careful", "tidy"},
+			IdleAnimationIDs: []string{"count_coins"}, DialogueTags: []string{"savings"}, Weight: 3,
+			XPBonusPercent: map[XPEventSource]int{XPSourceReceipt: 20}},
+		{PersonalityID: 3, Code: "loyal", Name: "Loyal", Traits: []string{"steady", "warm"},
+			IdleAnimationIDs: []string{"wave"}, DialogueTags: []string{"greetings"}, Weight: 2,
+			XPBonusPercent: map[XPEventSource]int{XPSourceDaily: 50}},
+		{PersonalityID: 4, Code: "sleepy", Name: "Sleepy", Traits: []string{"calm", "dreamy"},
+			IdleAnimationIDs: []string{"yawn", "nap"}, DialogueTags: []string{"dreams"}, Weight: 2,
+			XPBonusPercent: map[XPEventSource]int{}},
+	}
+}
+
+// BoostXP applies the personality's bonus for source to an XP amount. The
+// bonus is truncated to whole XP, so small amounts may gain nothing.
+func (p *Personality) BoostXP(source XPEventSource, xp int) int {
+	if p == nil {
+		return xp
+	}
+	return xp + xp*p.XPBonusPercent[source]/100
+}
+
+// BoostRule scales a source's per-action amount and daily cap by the
+// personality's bonus, so the bonus is extra XP rather than a faster path to
+// the same cap.
+func (p *Personality) BoostRule(source XPEventSource, rule XPSourceRule) XPSourceRule {
+	return XPSourceRule{
+		MaxDaily:  p.BoostXP(source, rule.MaxDaily),
+		PerAction: p.BoostXP(source, rule.PerAction),
+	}
+}
+
+// PickWeightedPersonality draws one personality with probability proportional
+// to its weight, using intN (such as rand.IntN) to pick a number in [0, n).
+// It returns nil when no personality has a positive weight.
+func PickWeightedPersonality(catalog []*Personality, intN func(n int) int) *Personality {
+	total := 0
+	for _, p := range catalog {
+		if p.Weight > 0 {
+			total += p.Weight
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	n := intN(total)
+	for _, p := range catalog {
+		if p.Weight <= 0 {
+			continue
+		}
+		if n < p.Weight {
+			return p
+		}
+		n -= p.Weight
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPickWeightedPersonality(t *testing.T) {
+	catalog := []*Personality{
+		{PersonalityID: 1, Weight: 3},
+		{PersonalityID: 2, Weight: 0},
+		{PersonalityID: 3, Weight: -4},
+		{PersonalityID: 4, Weight: 1},
+	}
+	// Draws 0-2 land on the first personality and 3 on the last; zero and
+	// negative weights are never picked.
+	for n, want := range []int{1, 1, 1, 4} {
+		var total int
+		p := PickWeightedPersonality(catalog, func(max int) int {
+			total = max
+			return n
+		})
+		require.Equal(t, 4, total)
+		require.Equal(t, want, p.PersonalityID, "draw %d", n)
+	}
+
+	require.Nil(t, PickWeightedPersonality(catalog[1:3], func(int) int {
+		t.Fatal("nothing to draw from")
+		return 0
+	}))
+	require.Nil(t, PickWeightedPersonality(nil, rand.IntN))
+}
+
+func TestPickWeightedPersonalityDistribution(t *testing.T) {
+	catalog := StarterPersonalities()
+	var ptrs []*Personality
+	total := 0
+	for i := range catalog {
+		ptrs = append(ptrs, &catalog[i])
+		total += catalog[i].Weight
+	}
+
+	// A seeded source keeps the counts reproducible.
+	rng := rand.New(rand.NewPCG(1, 2))
+	const draws = 10000
+	counts := map[int]int{}
+	for i := 0; i < draws; i++ {
+		counts[PickWeightedPersonality(ptrs, rng.IntN).PersonalityID]++
+	}
+	for _, p := range catalog {
+		want := float64(draws*p.Weight) / float64(total)
+		require.InDelta(t, want, counts[p.PersonalityID], want*0.1, p.Code)
+	}
+}
+
+func TestBoostXP(t *testing.T) {
+	p := &Personality{XPBonusPercent: map[XPEventSource]int{
+		XPSourcePlay:    20,
+		XPSourceReceipt: 15,
+		XPSourceDaily:   -25,
+	}}
+	tests := []struct {
+		name   string
+		source XPEventSource
+		xp     int
+		want   int
+	}{
+		{"whole bonus", XPSourcePlay, 20, 24},
+		{"bonus truncated", XPSourceReceipt, 10, 11},
+		{"bonus below one XP", XPSourceReceipt, 6, 6},
+		{"penalty truncated toward zero", XPSourceDaily, 10, 8},
+		{"source without a bonus", XPEventSource("QUEST"), 10, 10},
+		{"zero", XPSourcePlay, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, p.BoostXP(tt.source, tt.xp))
+		})
+	}
+
+	var none *Personality
+	require.Equal(t, 10, none.BoostXP(XPSourcePlay, 10))
+	require.Equal(t, XPSourceRule{MaxDaily: 120, PerAction: 24},
+		p.BoostRule(XPSourcePlay, XPSourceRule{MaxDaily: 100, PerAction: 20}))
+}
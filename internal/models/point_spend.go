@@ -4,19 +4,21 @@ import "time"
 
 // Transaction Models
 
-type SpendPointsRequest struct {
-	UserID string `json:"user_id" binding:"required"`
-	ItemID string `json:"item_id" binding:"required"`
-	Amount int    `json:"amount" binding:"required"`
+type PointSpend struct {
+	SpendID     int64      `json:"spend_id" db:"spend_id"`
+	UserID      int64      `json:"user_id" db:"user_id"`
+	PointlingID *int64     `json:"pointling_id,omitempty" db:"pointling_id"`
+	ItemID      int64      `json:"item_id" db:"item_id"`
+	PointsSpent int        `json:"points_spent" db:"points_spent"`
+	SpendTS     time.Time  `json:"spend_ts" db:"spend_ts"`
+	ReversedAt  *time.Time `json:"reversed_at,omitempty" db:"reversed_at"`
+	Item        *Item      `json:"item,omitempty" db:"-"`
 }
 
-type PointSpend struct {
-	SpendID     int64     `json:"spend_id" db:"spend_id"`
-	UserID      int64     `json:"user_id" db:"user_id"`
-	ItemID      int64     `json:"item_id" db:"item_id"`
-	PointsSpent int       `json:"points_spent" db:"points_spent"`
-	SpendTS     time.Time `json:"spend_ts" db:"spend_ts"`
-	Item        *Item     `json:"item,omitempty" db:"-"`
+type PurchaseItemRequest struct {
+	UserID      string `json:"user_id" binding:"required"`
+	PointlingID string `json:"-"`
+	ItemID      string `json:"-"`
 }
 
 type TransactionSuccess struct {
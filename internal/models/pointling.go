@@ -4,24 +4,26 @@ import "time"
 
 // Pointling Models
 type Pointling struct {
-	PointlingID   int64     `json:"pointling_id" db:"pointling_id"`
-	UserID        int64     `json:"user_id" db:"user_id"`
-	Nickname      *string   `json:"nickname,omitempty" db:"nickname"`
-	Level         int       `json:"level" db:"level"`
-	CurrentXP     int       `json:"current_xp" db:"current_xp"`
-	RequiredXP    int       `json:"required_xp" db:"required_xp"`
-	PersonalityID *int      `json:"personality_id,omitempty" db:"personality_id"`
-	LookJSON      JSONMap   `json:"look_json" db:"look_json"`
-	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	PointlingID   int64        `json:"pointling_id" db:"pointling_id"`
+	UserID        int64        `json:"user_id" db:"user_id"`
+	Nickname      *string      `json:"nickname,omitempty" db:"nickname"`
+	Level         int          `json:"level" db:"level"`
+	CurrentXP     int          `json:"current_xp" db:"current_xp"`
+	RequiredXP    int          `json:"required_xp" db:"required_xp"`
+	PersonalityID *int         `json:"personality_id,omitempty" db:"personality_id"`
+	Personality   *Personality `json:"personality,omitempty" db:"-"`
+	Look          Look         `json:"look_json" db:"look_json"`
+	CreatedAt     time.Time    `json:"created_at" db:"created_at"`
 }
 
 type CreatePointlingRequest struct {
-	UserID string `json:"user_id" binding:"required"`
-	Name   string `json:"name" binding:"required"`
+	UserID        string `json:"user_id" binding:"required"`
+	Name          string `json:"name" binding:"required"`
+	PersonalityID *int   `json:"personality_id"`
 }
 
 type UpdateNicknameRequest struct {
-	PointlingID string `json:"pointling_id" binding:"required"`
+	PointlingID string `json:"pointling_id"`
 	Nickname    string `json:"nickname" binding:"required"`
 }
 
@@ -35,19 +37,27 @@ func NewPointling(userID int64, nickname *string) *Pointling {
 		Nickname:   nickname,
 		Level:      1,
 		CurrentXP:  0,
-		RequiredXP: 3,
-		LookJSON:   make(JSONMap),
+		RequiredXP: CalculateNextLevelXP(1),
+		Look:       NewLook(),
 	}
 }
 
+// CalculateNextLevelXP applies the built-in curve; live XP gains use the
+// stored XPConfig curve instead.
 func CalculateNextLevelXP(currentLevel int) int {
-	baseXP := 3
-	xpPerLevel := 3
-	maxXP := 120
+	return DefaultXPConfig().Curve.RequiredXP(currentLevel)
+}
 
-	required := baseXP + (currentLevel-1)*xpPerLevel
-	if required > maxXP {
-		required = maxXP
+// ApplyXP adds gained XP to the pointling and rolls any overflow into as many
+// levels as it covers on curve. It returns every level reached, in order.
+func (p *Pointling) ApplyXP(gain int, curve LevelCurve) []int {
+	var levels []int
+	p.CurrentXP += gain
+	for p.RequiredXP > 0 && p.CurrentXP >= p.RequiredXP {
+		p.CurrentXP -= p.RequiredXP
+		p.Level++
+		p.RequiredXP = curve.RequiredXP(p.Level)
+		levels = append(levels, p.Level)
 	}
-	return required
+	return levels
 }
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyXP(t *testing.T) {
+	// Requirements run 3, 6, 9 and then stay at the cap of 9.
+	curve := LevelCurve{Type: CurveLinear, Base: 3, PerLevel: 3, Max: 9}
+
+	tests := []struct {
+		name         string
+		gain         int
+		wantLevels   []int
+		wantLevel    int
+		wantXP       int
+		wantRequired int
+	}{
+		{"below requirement", 2, nil, 1, 2, 3},
+		{"exactly one level", 3, []int{2}, 2, 0, 6},
+		{"several levels", 3 + 6 + 1, []int{2, 3}, 3, 1, 9},
+		{"rollover past the cap", 3 + 6 + 9 + 9 + 4, []int{2, 3, 4, 5}, 5, 4, 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Pointling{Level: 1, RequiredXP: curve.RequiredXP(1)}
+
+			levels := p.ApplyXP(tt.gain, curve)
+
+			require.Equal(t, tt.wantLevels, levels)
+			require.Equal(t, tt.wantLevel, p.Level)
+			require.Equal(t, tt.wantXP, p.CurrentXP)
+			require.Equal(t, tt.wantRequired, p.RequiredXP)
+		})
+	}
+}
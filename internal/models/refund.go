@@ -0,0 +1,34 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Refund Models
+
+var ErrSpendNotFound = NewNotFoundError(errors.New("point spend not found"))
+
+type RefundSpendRequest struct {
+	SpendID string `json:"-"`
+	Reason  string `json:"reason" binding:"required"`
+	// PointlingID names the pointling to revoke the item from for spends
+	// recorded before purchases stored it.
+	PointlingID *string `json:"pointling_id"`
+}
+
+// SpendReversal is the audit record of a refunded spend. There is at most one
+// per spend, which is what makes refunds idempotent.
+type SpendReversal struct {
+	SpendID         int64     `json:"spend_id" db:"spend_id"`
+	UserID          int64     `json:"user_id" db:"user_id"`
+	PointlingID     *int64    `json:"pointling_id,omitempty" db:"pointling_id"`
+	ItemID          int64     `json:"item_id" db:"item_id"`
+	PointsRefunded  int       `json:"points_refunded" db:"points_refunded"`
+	LedgerEntryID   int64     `json:"ledger_entry_id" db:"ledger_entry_id"`
+	ItemRevoked     bool      `json:"item_revoked" db:"item_revoked"`
+	Reason          string    `json:"reason" db:"reason"`
+	Actor           string    `json:"actor" db:"actor"`
+	ReversedAt      time.Time `json:"reversed_at" db:"reversed_at"`
+	AlreadyReversed bool      `json:"already_reversed" db:"-"`
+}
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+)
+
+// Render Models
+
+const (
+	// RenderSlotBody and RenderSlotFeature label layers that do not come from
+	// an equipment slot.
+	RenderSlotBody    = "BODY"
+	RenderSlotFeature = "FEATURE"
+)
+
+// renderZIndex orders layers back to front. Wings sit behind the body;
+// everything else stacks on top of it.
+var renderZIndex = map[string]int{
+	string(SlotWings): -10,
+	RenderSlotBody:    0,
+	RenderSlotFeature: 10,
+	string(SlotShoes): 20,
+	string(SlotFace):  30,
+	string(SlotHat):   40,
+}
+
+type RenderLayer struct {
+	AssetID string `json:"asset_id"`
+	Slot    string `json:"slot"`
+	ZIndex  int    `json:"z_index"`
+	Tint    string `json:"tint,omitempty"`
+}
+
+type RenderManifest struct {
+	PointlingID int64         `json:"pointling_id"`
+	BaseBody    string        `json:"base_body"`
+	Color       string        `json:"color,omitempty"`
+	Layers      []RenderLayer `json:"layers"`
+	ETag        string        `json:"-"`
+}
+
+// BaseBodyAssetID names the sprite asset for a base body.
+func BaseBodyAssetID(baseBody string) string {
+	return "body_" + baseBody
+}
+
+// BuildRenderManifest orders the base body, owned features and equipped
+// accessories into back-to-front layers. Body-colored layers carry the
+// active color as their tint.
+func BuildRenderManifest(p *Pointling, owned []*PointlingItem) RenderManifest {
+	m := RenderManifest{
+		PointlingID: p.PointlingID,
+		BaseBody:    p.Look.BaseBody,
+		Color:       p.Look.Color,
+	}
+	m.Layers = append(m.Layers, RenderLayer{
+		AssetID: BaseBodyAssetID(p.Look.BaseBody),
+		Slot:    RenderSlotBody,
+		ZIndex:  renderZIndex[RenderSlotBody],
+		Tint:    p.Look.Color,
+	})
+
+	for _, pi := range owned {
+		if pi.Item == nil {
+			continue
+		}
+		switch {
+		case pi.Item.Category == CategoryFeature:
+			m.Layers = append(m.Layers, RenderLayer{
+				AssetID: pi.Item.AssetID,
+				Slot:    RenderSlotFeature,
+				ZIndex:  renderZIndex[RenderSlotFeature],
+				Tint:    p.Look.Color,
+			})
+		case pi.Equipped && pi.Item.Slot != nil:
+			slot := string(*pi.Item.Slot)
+			m.Layers = append(m.Layers, RenderLayer{
+				AssetID: pi.Item.AssetID,
+				Slot:    slot,
+				ZIndex:  renderZIndex[slot],
+			})
+		}
+	}
+
+	sort.SliceStable(m.Layers, func(i, j int) bool {
+		if m.Layers[i].ZIndex != m.Layers[j].ZIndex {
+			return m.Layers[i].ZIndex < m.Layers[j].ZIndex
+		}
+		return m.Layers[i].AssetID < m.Layers[j].AssetID
+	})
+
+	m.ETag = m.computeETag()
+	return m
+}
+
+// computeETag hashes everything a client draws, so the tag changes exactly
+// when the rendered avatar would.
+func (m RenderManifest) computeETag() string {
+	payload, _ := json.Marshal(struct {
+		BaseBody string        `json:"base_body"`
+		Color    string        `json:"color"`
+		Layers   []RenderLayer `json:"layers"`
+	}{m.BaseBody, m.Color, m.Layers})
+	sum := sha256.Sum256(payload)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
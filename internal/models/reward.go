@@ -0,0 +1,82 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Level Reward Models
+
+type RewardType string
+
+const (
+	RewardTypeItem RewardType = "ITEM"
+	// RewardTypeColor is no longer offered; older offers may still hold it.
+	RewardTypeColor RewardType = "COLOR"
+
+	// RewardOptionsPerLevel is how many candidates a level-up offers.
+	RewardOptionsPerLevel = 3
+
+	// RewardOfferTTL is how long a level-up offer stays claimable.
+	RewardOfferTTL = 7 * 24 * time.Hour
+)
+
+var (
+	ErrRewardNotFound       = NewNotFoundError(errors.New("reward offer not found"))
+	ErrRewardAlreadyClaimed = NewConflictError(errors.New("reward already claimed"))
+	ErrRewardExpired        = NewConflictError(errors.New("reward offer expired"))
+	ErrInvalidRewardOption  = NewValidationError(errors.New("option is not part of this reward offer"))
+)
+
+type RewardOption struct {
+	Type RewardType `json:"type" binding:"required"`
+	ID   string     `json:"id" binding:"required"`
+}
+
+type LevelReward struct {
+	RewardID     int64          `json:"reward_id" db:"reward_id"`
+	PointlingID  int64          `json:"pointling_id" db:"pointling_id"`
+	Level        int            `json:"level" db:"level"`
+	Options      []RewardOption `json:"options" db:"options"`
+	ChosenOption *RewardOption  `json:"chosen_option,omitempty" db:"chosen_option"`
+	OfferedAt    time.Time      `json:"offered_at" db:"offered_at"`
+	ExpiresAt    time.Time      `json:"expires_at" db:"expires_at"`
+	ClaimedAt    *time.Time     `json:"claimed_at,omitempty" db:"claimed_at"`
+}
+
+type ClaimRewardRequest struct {
+	PointlingID string       `json:"pointling_id"`
+	RewardID    string       `json:"reward_id" binding:"required"`
+	Option      RewardOption `json:"option" binding:"required"`
+}
+
+type LevelUpOptionsResponse struct {
+	RewardID  int64          `json:"reward_id"`
+	Level     int            `json:"level"`
+	ExpiresAt time.Time      `json:"expires_at"`
+	Options   []RewardOption `json:"options"`
+}
+
+type PendingRewardsResponse struct {
+	Rewards []LevelUpOptionsResponse `json:"rewards"`
+}
+
+// HasOption reports whether opt was one of the offered candidates.
+func (r *LevelReward) HasOption(opt RewardOption) bool {
+	for _, o := range r.Options {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// ToOptionsResponse converts a stored offer into its API representation.
+func (r *LevelReward) ToOptionsResponse() LevelUpOptionsResponse {
+	return LevelUpOptionsResponse{
+		RewardID:  r.RewardID,
+		Level:     r.Level,
+		ExpiresAt: r.ExpiresAt,
+		Options:   r.Options,
+	}
+}
@@ -0,0 +1,44 @@
+package models
+
+import "errors"
+
+// Point Spend History Models
+
+const (
+	DefaultSpendHistoryLimit = 50
+	MaxSpendHistoryLimit     = 200
+
+	// SpendHistoryFormatCSV exports the whole filtered history as a CSV file.
+	SpendHistoryFormatCSV = "csv"
+)
+
+var ErrInvalidSpendHistoryQuery = NewValidationError(errors.New("invalid spend history query"))
+
+type SpendHistoryFilter struct {
+	UserID int64
+	TimeRange
+	Cursor *HistoryCursor
+	Limit  int
+	Offset int
+}
+
+type SpendHistoryRequest struct {
+	UserID string `form:"-"`
+	From   string `form:"from"`
+	To     string `form:"to"`
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit"`
+	Offset int    `form:"offset"`
+	Format string `form:"format"`
+}
+
+type SpendHistoryResponse struct {
+	Spends     []PointSpend `json:"spends"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+	TotalSpent int64        `json:"total_spent"`
+}
+
+// SpendCursorAfter returns the cursor that continues a page ending with s.
+func SpendCursorAfter(s *PointSpend) HistoryCursor {
+	return HistoryCursor{TS: s.SpendTS, ID: s.SpendID}
+}
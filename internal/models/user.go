@@ -1,27 +1,60 @@
 package models
 
-import "time"
+import (
+	"errors"
+	"time"
+)
 
 // User Models
 
+// DefaultTimezone is used for users that never chose a timezone.
+const DefaultTimezone = "UTC"
+
+var ErrInvalidTimezone = NewValidationError(errors.New("invalid IANA timezone"))
+
 type User struct {
 	UserID       int64     `json:"user_id" db:"user_id"`
 	DisplayName  string    `json:"display_name" db:"display_name"`
 	PointBalance int64     `json:"point_balance" db:"point_balance"`
+	Timezone     string    `json:"timezone" db:"timezone"`
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 }
 
+// CreateUserRequest has no starting balance: new users start at zero and are
+// only credited through the admin points route, which is audited.
 type CreateUserRequest struct {
-	UserID       string `json:"user_id" binding:"required"`
-	DisplayName  string `json:"display_name" binding:"required"`
-	PointBalance int    `json:"point_balance" binding:"required"`
+	UserID      string `json:"user_id" binding:"required"`
+	DisplayName string `json:"display_name" binding:"required"`
+	Timezone    string `json:"timezone"`
+}
+
+type UpdateUserTimezoneRequest struct {
+	UserID   string `json:"user_id"`
+	Timezone string `json:"timezone" binding:"required"`
 }
 
+// UpdateUserPointsRequest credits (positive PointAmount) or debits (negative)
+// a user's balance through the points ledger.
 type UpdateUserPointsRequest struct {
-	UserID      string `json:"user_id" binding:"required"`
-	PointAmount int    `json:"point_amount" binding:"required"`
+	UserID      string       `json:"user_id"`
+	PointAmount int          `json:"point_amount" binding:"required"`
+	Reason      LedgerReason `json:"reason" binding:"required"`
+	Reference   *string      `json:"reference"`
 }
 
 type UserListResponse struct {
 	Users []User `json:"users"`
 }
+
+// Location resolves the user's timezone, falling back to UTC when it is
+// unset or unknown to this host.
+func (u *User) Location() *time.Location {
+	if u.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FieldError explains why one request field was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// FieldErrors collects every rejected field of a request so the client can
+// fix them all at once. Problem responses list them under "errors".
+type FieldErrors []FieldError
+
+func (fe FieldErrors) Error() string {
+	parts := make([]string, len(fe))
+	for i, e := range fe {
+		parts[i] = e.Field + ": " + e.Message
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Add records that field was rejected with message.
+func (fe *FieldErrors) Add(field, message string) {
+	*fe = append(*fe, FieldError{Field: field, Message: message})
+}
+
+// Err returns nil when no field was rejected, or a validation error listing them.
+func (fe FieldErrors) Err() error {
+	if len(fe) == 0 {
+		return nil
+	}
+	return NewValidationError(fe)
+}
+
+// ParseID parses raw as a positive decimal ID. A malformed value is recorded
+// against field and 0 is returned.
+func (fe *FieldErrors) ParseID(field, raw string) int64 {
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id <= 0 {
+		fe.Add(field, "must be a positive integer")
+		return 0
+	}
+	return id
+}
+
+// ParseID parses a single positive decimal ID named field.
+func ParseID(field, raw string) (int64, error) {
+	var fe FieldErrors
+	id := fe.ParseID(field, raw)
+	return id, fe.Err()
+}
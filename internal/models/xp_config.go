@@ -0,0 +1,192 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// XP Config Models
+
+type LevelCurveType string
+
+const (
+	// CurveLinear requires Base + (level-1)*PerLevel XP, capped at Max.
+	CurveLinear LevelCurveType = "LINEAR"
+	// CurveExponential requires Base * Growth^(level-1) XP, capped at Max.
+	CurveExponential LevelCurveType = "EXPONENTIAL"
+	// CurveTable reads the requirement for level n from Table[n-1]; levels past
+	// the end of the table reuse its last entry.
+	CurveTable LevelCurveType = "TABLE"
+
+	DefaultBaseXP      = 3
+	DefaultXPPerLevel  = 3
+	DefaultMaxLevelXP  = 120
+	DefaultPerActionXP = 10
+)
+
+var ErrInvalidXPConfig = NewValidationError(errors.New("invalid xp config"))
+
+var xpSourceNamePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,31}$`)
+
+type LevelCurve struct {
+	Type     LevelCurveType `json:"type" binding:"required"`
+	Base     int            `json:"base,omitempty"`
+	PerLevel int            `json:"per_level,omitempty"`
+	Growth   float64        `json:"growth,omitempty"`
+	Max      int            `json:"max,omitempty"`
+	Table    []int          `json:"table,omitempty"`
+}
+
+type XPSourceRule struct {
+	MaxDaily  int `json:"max_daily"`
+	PerAction int `json:"per_action"`
+}
+
+// XPConfig is one immutable version of the leveling curve and XP source rules.
+type XPConfig struct {
+	Version   int                            `json:"version" db:"version"`
+	Curve     LevelCurve                     `json:"curve" db:"-"`
+	Sources   map[XPEventSource]XPSourceRule `json:"sources" db:"-"`
+	CreatedAt time.Time                      `json:"created_at" db:"created_at"`
+}
+
+type UpdateXPConfigRequest struct {
+	Curve   LevelCurve                     `json:"curve" binding:"required"`
+	Sources map[XPEventSource]XPSourceRule `json:"sources" binding:"required"`
+}
+
+type XPConfigListResponse struct {
+	Versions []XPConfig `json:"versions"`
+}
+
+// DefaultXPConfig is the built-in configuration used until a version is stored.
+func DefaultXPConfig() *XPConfig {
+	sources := make(map[XPEventSource]XPSourceRule, len(AllXPSources))
+	for _, s := range AllXPSources {
+		sources[s] = XPSourceRule{MaxDaily: s.GetMaxDailyXP(), PerAction: s.GetXPPerAction()}
+	}
+	return &XPConfig{
+		Curve: LevelCurve{
+			Type:     CurveLinear,
+			Base:     DefaultBaseXP,
+			PerLevel: DefaultXPPerLevel,
+			Max:      DefaultMaxLevelXP,
+		},
+		Sources: sources,
+	}
+}
+
+// RequiredXP returns the XP needed to advance from level to level+1.
+func (c LevelCurve) RequiredXP(level int) int {
+	if level < 1 {
+		level = 1
+	}
+
+	var required int
+	switch c.Type {
+	case CurveLinear:
+		required = c.Base + (level-1)*c.PerLevel
+	case CurveExponential:
+		xp := float64(c.Base) * math.Pow(c.Growth, float64(level-1))
+		if c.Max > 0 && xp > float64(c.Max) {
+			return c.Max
+		}
+		required = int(math.Round(xp))
+	case CurveTable:
+		if len(c.Table) == 0 {
+			return 0
+		}
+		if level > len(c.Table) {
+			level = len(c.Table)
+		}
+		return c.Table[level-1]
+	default:
+		return 0
+	}
+
+	if c.Max > 0 && required > c.Max {
+		required = c.Max
+	}
+	return required
+}
+
+// Rule looks up the rule for an XP source.
+func (c *XPConfig) Rule(source XPEventSource) (XPSourceRule, bool) {
+	rule, ok := c.Sources[source]
+	return rule, ok
+}
+
+// SourceNames lists the configured XP sources in alphabetical order.
+func (c *XPConfig) SourceNames() []XPEventSource {
+	names := make([]XPEventSource, 0, len(c.Sources))
+	for s := range c.Sources {
+		names = append(names, s)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+// Validate reports every problem with the config in a single error.
+func (c *XPConfig) Validate() error {
+	var problems []string
+
+	switch c.Curve.Type {
+	case CurveLinear:
+		if c.Curve.Base <= 0 {
+			problems = append(problems, "curve.base must be positive")
+		}
+		if c.Curve.PerLevel < 0 {
+			problems = append(problems, "curve.per_level must not be negative")
+		}
+		if c.Curve.Max != 0 && c.Curve.Max < c.Curve.Base {
+			problems = append(problems, "curve.max must be at least curve.base")
+		}
+	case CurveExponential:
+		if c.Curve.Base <= 0 {
+			problems = append(problems, "curve.base must be positive")
+		}
+		if c.Curve.Growth < 1 {
+			problems = append(problems, "curve.growth must be at least 1")
+		}
+		if c.Curve.Max < c.Curve.Base {
+			problems = append(problems, "curve.max is required and must be at least curve.base")
+		}
+	case CurveTable:
+		if len(c.Curve.Table) == 0 {
+			problems = append(problems, "curve.table must not be empty")
+		}
+		for i, xp := range c.Curve.Table {
+			if xp <= 0 {
+				problems = append(problems, fmt.Sprintf("curve.table[%d] must be positive", i))
+			}
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("curve.type must be one of %s, %s, %s", CurveLinear, CurveExponential, CurveTable))
+	}
+
+	if len(c.Sources) == 0 {
+		problems = append(problems, "sources must define at least one source")
+	}
+	for _, name := range c.SourceNames() {
+		rule := c.Sources[name]
+		if !xpSourceNamePattern.MatchString(string(name)) {
+			problems = append(problems, fmt.Sprintf("sources.%s: name must be upper-case letters, digits or underscores", name))
+		}
+		if rule.MaxDaily <= 0 {
+			problems = append(problems, fmt.Sprintf("sources.%s.max_daily must be positive", name))
+		}
+		if rule.PerAction <= 0 || rule.PerAction > rule.MaxDaily {
+			problems = append(problems, fmt.Sprintf("sources.%s.per_action must be between 1 and max_daily", name))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidXPConfig, strings.Join(problems, "; "))
+	}
+	return nil
+}
@@ -19,7 +19,13 @@ const (
 	MaxDailyLoginXP   = 10
 )
 
-var ErrDailyXPLimitReached = errors.New("daily XP limit reached for this source")
+// AllXPSources lists the built-in XP sources; stored XP configs may add more.
+var AllXPSources = []XPEventSource{XPSourceReceipt, XPSourcePlay, XPSourceDaily}
+
+var (
+	ErrDailyXPLimitReached = NewLimitReachedError(errors.New("daily XP limit reached for this source"))
+	ErrInvalidXPSource     = NewValidationError(errors.New("invalid XP source"))
+)
 
 type XPEvent struct {
 	EventID     int64         `json:"event_id" db:"event_id"`
@@ -29,22 +35,46 @@ type XPEvent struct {
 	EventTS     time.Time     `json:"event_ts" db:"event_ts"`
 }
 
-type AddXPRequest struct {
-	PointlingID string `json:"pointling_id" binding:"required"`
-	XPGain      int    `json:"xp_gain" binding:"required"`
+// DayWindow is one calendar day [Start, End) in some timezone.
+type DayWindow struct {
+	Start time.Time
+	End   time.Time
 }
 
-type XPUpdateResponse struct {
-	LeveledUp  bool `json:"leveled_up"`
-	NewLevel   int  `json:"new_level"`
-	RequiredXP int  `json:"required_xp"`
+// XPDailyCap bounds how much XP a source may grant within a day window.
+type XPDailyCap struct {
+	Window DayWindow
+	Max    int
+}
+
+type XPSourceLimit struct {
+	Source      XPEventSource `json:"source"`
+	MaxDaily    int           `json:"max_daily"`
+	EarnedToday int           `json:"earned_today"`
+	Remaining   int           `json:"remaining"`
+}
+
+type XPLimitsResponse struct {
+	Timezone string          `json:"timezone"`
+	ResetAt  time.Time       `json:"reset_at"`
+	Limits   []XPSourceLimit `json:"limits"`
 }
 
-type LevelUpOptionsResponse struct {
-	Options []struct {
-		Type string `json:"type"`
-		ID   string `json:"id"`
-	} `json:"options"`
+type AddXPRequest struct {
+	PointlingID string        `json:"pointling_id"`
+	Source      XPEventSource `json:"source" binding:"required"`
+}
+
+type XPUpdateResponse struct {
+	// XPGained is less than the source grants when the gain reached the cap.
+	XPGained       int                      `json:"xp_gained"`
+	LeveledUp      bool                     `json:"leveled_up"`
+	NewLevel       int                      `json:"new_level"`
+	CurrentXP      int                      `json:"current_xp"`
+	RequiredXP     int                      `json:"required_xp"`
+	LevelsCrossed  []int                    `json:"levels_crossed,omitempty"`
+	ColorsUnlocked []string                 `json:"colors_unlocked,omitempty"`
+	Rewards        []LevelUpOptionsResponse `json:"rewards,omitempty"`
 }
 
 func (s XPEventSource) ValidateSource() bool {
@@ -81,3 +111,11 @@ func (s XPEventSource) GetXPPerAction() int {
 		return 0
 	}
 }
+
+// DailyWindow returns the calendar day containing now in loc. The end is the
+// next local midnight, so days spanning a DST change are not 24 hours long.
+func DailyWindow(now time.Time, loc *time.Location) DayWindow {
+	local := now.In(loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	return DayWindow{Start: start, End: start.AddDate(0, 0, 1)}
+}
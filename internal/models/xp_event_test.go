@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDailyWindow(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		loc       *time.Location
+		wantStart time.Time
+		wantHours float64
+	}{
+		{"plain day", time.Date(2026, 10, 16, 12, 0, 0, 0, ny), ny, time.Date(2026, 10, 16, 0, 0, 0, 0, ny), 24},
+		{"spring forward", time.Date(2026, 3, 8, 23, 30, 0, 0, ny), ny, time.Date(2026, 3, 8, 0, 0, 0, 0, ny), 23},
+		// 23:30 is 24.5 hours after midnight on this day, but still the same day.
+		{"fall back", time.Date(2026, 11, 1, 23, 30, 0, 0, ny), ny, time.Date(2026, 11, 1, 0, 0, 0, 0, ny), 25},
+		// 20:00 UTC is already tomorrow in Tokyo.
+		{"ahead of UTC", time.Date(2026, 10, 16, 20, 0, 0, 0, time.UTC), tokyo, time.Date(2026, 10, 17, 0, 0, 0, 0, tokyo), 24},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := DailyWindow(tt.now, tt.loc)
+			require.True(t, tt.wantStart.Equal(w.Start), "start %s", w.Start)
+			require.Equal(t, tt.wantHours, w.End.Sub(w.Start).Hours())
+			require.True(t, w.End.Equal(time.Date(tt.wantStart.Year(), tt.wantStart.Month(), tt.wantStart.Day()+1, 0, 0, 0, 0, tt.loc)),
+				"the window ends at the next local midnight")
+		})
+	}
+}
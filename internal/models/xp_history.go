@@ -0,0 +1,56 @@
+package models
+
+import "errors"
+
+// XP History Models
+
+const (
+	DefaultXPHistoryLimit = 50
+	MaxXPHistoryLimit     = 200
+
+	// XPHistoryAggregateDaily groups history into per-day, per-source totals.
+	XPHistoryAggregateDaily = "daily"
+
+	// DefaultXPHistoryDays is the window used by the daily aggregate when no
+	// start time is given.
+	DefaultXPHistoryDays = 7
+)
+
+var ErrInvalidXPHistoryQuery = NewValidationError(errors.New("invalid xp history query"))
+
+type XPHistoryFilter struct {
+	PointlingID int64
+	Source      *XPEventSource
+	TimeRange
+	Cursor   *HistoryCursor
+	Limit    int
+	TimeZone string
+}
+
+type XPHistoryRequest struct {
+	PointlingID string `form:"-"`
+	Source      string `form:"source"`
+	From        string `form:"from"`
+	To          string `form:"to"`
+	Cursor      string `form:"cursor"`
+	Limit       int    `form:"limit"`
+	Aggregate   string `form:"aggregate"`
+}
+
+type XPDailyTotal struct {
+	Day        string        `json:"day"`
+	Source     XPEventSource `json:"source"`
+	TotalXP    int           `json:"total_xp"`
+	EventCount int           `json:"event_count"`
+}
+
+type XPHistoryResponse struct {
+	Events     []XPEvent      `json:"events,omitempty"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+	Daily      []XPDailyTotal `json:"daily,omitempty"`
+}
+
+// CursorAfter returns the cursor that continues a page ending with e.
+func CursorAfter(e *XPEvent) HistoryCursor {
+	return HistoryCursor{TS: e.EventTS, ID: e.EventID}
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"my-pointlings-be/internal/models"
+)
+
+func (r *PointlingRepository) CreateAuditEntry(ctx context.Context, entry *models.AuditEntry) error {
+	query := `
+		INSERT INTO public.admin_audit_log (
+			actor, action, target_type, target_id, before, after
+		) VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING audit_id, created_at`
+
+	err := r.txWrapper().QueryRowContext(ctx, query,
+		entry.Actor,
+		entry.Action,
+		entry.TargetType,
+		entry.TargetID,
+		[]byte(entry.Before),
+		[]byte(entry.After),
+	).Scan(&entry.AuditID, &entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("create audit entry: %w", err)
+	}
+	return nil
+}
+
+func (r *PointlingRepository) ListAuditEntries(ctx context.Context, filter models.AuditLogFilter) ([]*models.AuditEntry, error) {
+	query := `
+		SELECT audit_id, actor, action, target_type, target_id, before, after, created_at
+		FROM public.admin_audit_log
+		WHERE true`
+	var args []interface{}
+
+	if filter.BeforeID > 0 {
+		query += fmt.Sprintf(" AND audit_id < $%d", len(args)+1)
+		args = append(args, filter.BeforeID)
+	}
+	if filter.TargetType != "" {
+		query += fmt.Sprintf(" AND target_type = $%d", len(args)+1)
+		args = append(args, filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		query += fmt.Sprintf(" AND target_id = $%d", len(args)+1)
+		args = append(args, filter.TargetID)
+	}
+	query += fmt.Sprintf(" ORDER BY audit_id DESC LIMIT $%d", len(args)+1)
+	args = append(args, filter.Limit)
+
+	rows, err := r.txWrapper().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list audit entries query: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*models.AuditEntry
+	for rows.Next() {
+		entry := &models.AuditEntry{}
+		var before, after []byte
+		err := rows.Scan(
+			&entry.AuditID,
+			&entry.Actor,
+			&entry.Action,
+			&entry.TargetType,
+			&entry.TargetID,
+			&before,
+			&after,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan audit entry: %w", err)
+		}
+		entry.Before, entry.After = before, after
+		entries = append(entries, entry)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate audit entries: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"my-pointlings-be/internal/models"
+)
+
+func (r *PointlingRepository) ReserveIdempotencyKey(ctx context.Context, key, requestHash string, ttl time.Duration) (*models.IdempotencyRecord, bool, error) {
+	// An expired key is forgotten so the caller can take it over.
+	deleteQuery := `
+		DELETE FROM public.idempotency_keys
+		WHERE idem_key = $1
+		AND expires_at <= now()`
+
+	if _, err := r.txWrapper().ExecContext(ctx, deleteQuery, key); err != nil {
+		return nil, false, fmt.Errorf("expire idempotency key: %w", err)
+	}
+
+	insertQuery := `
+		INSERT INTO public.idempotency_keys (idem_key, request_hash, expires_at)
+		VALUES ($1, $2, now() + $3 * interval '1 second')
+		ON CONFLICT (idem_key) DO NOTHING
+		RETURNING idem_key, request_hash, status_code, content_type,
+			response_body, created_at, expires_at`
+
+	record, err := scanIdempotencyRecord(r.txWrapper().QueryRowContext(ctx, insertQuery, key, requestHash, int64(ttl/time.Second)))
+	if err == nil {
+		return record, true, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, false, fmt.Errorf("reserve idempotency key: %w", err)
+	}
+
+	existing, err := r.GetIdempotencyKey(ctx, key)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing == nil {
+		// The holder released it between our insert and select.
+		return nil, false, models.ErrIdempotencyKeyInFlight
+	}
+	return existing, false, nil
+}
+
+func (r *PointlingRepository) GetIdempotencyKey(ctx context.Context, key string) (*models.IdempotencyRecord, error) {
+	query := `
+		SELECT idem_key, request_hash, status_code, content_type,
+			response_body, created_at, expires_at
+		FROM public.idempotency_keys
+		WHERE idem_key = $1`
+
+	record, err := scanIdempotencyRecord(r.txWrapper().QueryRowContext(ctx, query, key))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get idempotency key: %w", err)
+	}
+	return record, nil
+}
+
+func (r *PointlingRepository) SaveIdempotentResponse(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	query := `
+		UPDATE public.idempotency_keys
+		SET status_code = $2, content_type = $3, response_body = $4
+		WHERE idem_key = $1`
+
+	result, err := r.txWrapper().ExecContext(ctx, query, key, statusCode, contentType, body)
+	if err != nil {
+		return fmt.Errorf("save idempotent response: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("idempotency key not found: %s", key)
+	}
+	return nil
+}
+
+func (r *PointlingRepository) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	query := `DELETE FROM public.idempotency_keys WHERE idem_key = $1`
+
+	if _, err := r.txWrapper().ExecContext(ctx, query, key); err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (r *PointlingRepository) PurgeExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	query := `DELETE FROM public.idempotency_keys WHERE expires_at <= now()`
+
+	result, err := r.txWrapper().ExecContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("purge idempotency keys: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("get rows affected: %w", err)
+	}
+	return rows, nil
+}
+
+func scanIdempotencyRecord(row rowScanner) (*models.IdempotencyRecord, error) {
+	record := &models.IdempotencyRecord{}
+	var contentType sql.NullString
+	err := row.Scan(
+		&record.Key,
+		&record.RequestHash,
+		&record.StatusCode,
+		&contentType,
+		&record.ResponseBody,
+		&record.CreatedAt,
+		&record.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	record.ContentType = contentType.String
+	return record, nil
+}
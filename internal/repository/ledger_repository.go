@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"my-pointlings-be/internal/models"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// uniqueViolation is the Postgres SQLSTATE for a unique constraint failure.
+const uniqueViolation = "23505"
+
+func (r *PointlingRepository) PostLedgerEntry(ctx context.Context, entry *models.LedgerEntry) error {
+	if err := entry.Reason.CheckDelta(entry.Delta); err != nil {
+		return err
+	}
+	if r.tx == nil {
+		return r.InTransaction(ctx, func(tx API) error {
+			return tx.PostLedgerEntry(ctx, entry)
+		})
+	}
+
+	// The balance update takes the user row lock, so entries for one user are
+	// written in the same order their running balances were computed.
+	balanceQuery := `
+		UPDATE public.users
+		SET point_balance = point_balance + $2
+		WHERE user_id = $1
+		AND point_balance + $2 >= 0
+		RETURNING point_balance`
+
+	err := r.txWrapper().QueryRowContext(ctx, balanceQuery, entry.UserID, entry.Delta).Scan(&entry.BalanceAfter)
+	if err == sql.ErrNoRows {
+		var exists bool
+		existsQuery := `SELECT EXISTS (SELECT 1 FROM public.users WHERE user_id = $1)`
+		if err := r.txWrapper().QueryRowContext(ctx, existsQuery, entry.UserID).Scan(&exists); err != nil {
+			return fmt.Errorf("check user: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("%w: %d", models.ErrUserNotFound, entry.UserID)
+		}
+		return models.ErrInsufficientBalance
+	}
+	if err != nil {
+		return fmt.Errorf("update balance: %w", err)
+	}
+
+	entry.CounterAccount = entry.Reason.CounterAccount()
+	insertQuery := `
+		INSERT INTO public.point_ledger (
+			user_id, delta, balance_after, reason, counter_account, reference
+		) VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING entry_id, created_at`
+
+	err = r.txWrapper().QueryRowContext(ctx, insertQuery,
+		entry.UserID,
+		entry.Delta,
+		entry.BalanceAfter,
+		entry.Reason,
+		entry.CounterAccount,
+		entry.Reference,
+	).Scan(&entry.EntryID, &entry.CreatedAt)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return models.ErrDuplicateLedgerEntry
+	}
+	if err != nil {
+		return fmt.Errorf("create ledger entry: %w", err)
+	}
+	return nil
+}
+
+func (r *PointlingRepository) GetLedgerEntries(ctx context.Context, userID int64, beforeID int64, limit int) ([]*models.LedgerEntry, error) {
+	query := `
+		SELECT entry_id, user_id, delta, balance_after, reason,
+			counter_account, reference, created_at
+		FROM public.point_ledger
+		WHERE user_id = $1`
+	args := []interface{}{userID}
+	if beforeID > 0 {
+		args = append(args, beforeID)
+		query += ` AND entry_id < $` + fmt.Sprint(len(args))
+	}
+	args = append(args, limit)
+	query += ` ORDER BY entry_id DESC LIMIT $` + fmt.Sprint(len(args))
+
+	rows, err := r.txWrapper().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get ledger query: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*models.LedgerEntry
+	for rows.Next() {
+		entry := &models.LedgerEntry{}
+		err := rows.Scan(
+			&entry.EntryID,
+			&entry.UserID,
+			&entry.Delta,
+			&entry.BalanceAfter,
+			&entry.Reason,
+			&entry.CounterAccount,
+			&entry.Reference,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan ledger entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate ledger: %w", err)
+	}
+	return entries, nil
+}
+
+func (r *PointlingRepository) GetLedgerBalance(ctx context.Context, userID int64) (int64, error) {
+	query := `
+		SELECT COALESCE(SUM(delta), 0)
+		FROM public.point_ledger
+		WHERE user_id = $1`
+
+	var balance int64
+	if err := r.txWrapper().QueryRowContext(ctx, query, userID).Scan(&balance); err != nil {
+		return 0, fmt.Errorf("get ledger balance: %w", err)
+	}
+	return balance, nil
+}
+
+func (r *PointlingRepository) BackfillOpeningBalances(ctx context.Context) (int64, error) {
+	// Users that predate the ledger get one opening entry for their stored
+	// balance. Users that already have entries are left for reconciliation.
+	query := `
+		INSERT INTO public.point_ledger (
+			user_id, delta, balance_after, reason, counter_account
+		)
+		SELECT u.user_id, u.point_balance, u.point_balance, $1, $2
+		FROM public.users u
+		WHERE u.point_balance > 0
+		AND NOT EXISTS (
+			SELECT 1 FROM public.point_ledger l WHERE l.user_id = u.user_id
+		)`
+
+	result, err := r.txWrapper().ExecContext(ctx, query,
+		models.LedgerReasonOpeningBalance,
+		models.LedgerReasonOpeningBalance.CounterAccount(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("backfill opening balances: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("get rows affected: %w", err)
+	}
+	return rows, nil
+}
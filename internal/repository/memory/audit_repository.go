@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+
+	"my-pointlings-be/internal/models"
+)
+
+func (r *Repository) CreateAuditEntry(ctx context.Context, entry *models.AuditEntry) error {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	r.data.lastAuditID++
+	entry.AuditID = r.data.lastAuditID
+	entry.CreatedAt = r.now()
+	r.data.audit = append(r.data.audit, copyAuditEntry(*entry))
+	return nil
+}
+
+func (r *Repository) ListAuditEntries(ctx context.Context, filter models.AuditLogFilter) ([]*models.AuditEntry, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// Entries are appended in ID order, so walk backwards for newest first.
+	var entries []*models.AuditEntry
+	for i := len(r.data.audit) - 1; i >= 0 && len(entries) < filter.Limit; i-- {
+		stored := r.data.audit[i]
+		if filter.BeforeID > 0 && stored.AuditID >= filter.BeforeID {
+			continue
+		}
+		if filter.TargetType != "" && stored.TargetType != filter.TargetType {
+			continue
+		}
+		if filter.TargetID != "" && stored.TargetID != filter.TargetID {
+			continue
+		}
+		entry := copyAuditEntry(stored)
+		entries = append(entries, &entry)
+	}
+	return entries, nil
+}
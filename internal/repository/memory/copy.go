@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+
+	"my-pointlings-be/internal/models"
+)
+
+// pointlingRow stores the look as JSON, as the look_json column does, so
+// reads go through Look.Scan and get the same defaults as from Postgres.
+type pointlingRow struct {
+	pointling models.Pointling
+	lookJSON  []byte
+}
+
+func newPointlingRow(p models.Pointling) (pointlingRow, error) {
+	lookJSON, err := json.Marshal(p.Look)
+	if err != nil {
+		return pointlingRow{}, err
+	}
+	p.Nickname = clonePtr(p.Nickname)
+	p.PersonalityID = clonePtr(p.PersonalityID)
+	p.Personality = nil
+	p.Look = models.Look{}
+	return pointlingRow{pointling: p, lookJSON: lookJSON}, nil
+}
+
+func (row pointlingRow) toModel() (*models.Pointling, error) {
+	p := row.pointling
+	p.Nickname = clonePtr(p.Nickname)
+	p.PersonalityID = clonePtr(p.PersonalityID)
+	if err := p.Look.Scan(row.lookJSON); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+func copyItem(item models.Item) models.Item {
+	item.Slot = clonePtr(item.Slot)
+	item.PricePoints = clonePtr(item.PricePoints)
+	item.UnlockLevel = clonePtr(item.UnlockLevel)
+	return item
+}
+
+func copySpend(spend models.PointSpend) models.PointSpend {
+	spend.PointlingID = clonePtr(spend.PointlingID)
+	spend.ReversedAt = clonePtr(spend.ReversedAt)
+	spend.Item = nil
+	return spend
+}
+
+func copyLedgerEntry(entry models.LedgerEntry) models.LedgerEntry {
+	entry.Reference = clonePtr(entry.Reference)
+	return entry
+}
+
+func copyReversal(reversal models.SpendReversal) models.SpendReversal {
+	reversal.PointlingID = clonePtr(reversal.PointlingID)
+	reversal.AlreadyReversed = false
+	return reversal
+}
+
+func copyReward(reward models.LevelReward) models.LevelReward {
+	reward.Options = slices.Clone(reward.Options)
+	reward.ChosenOption = clonePtr(reward.ChosenOption)
+	reward.ClaimedAt = clonePtr(reward.ClaimedAt)
+	return reward
+}
+
+func copyPersonality(p models.Personality) models.Personality {
+	p.Traits = slices.Clone(p.Traits)
+	p.IdleAnimationIDs = slices.Clone(p.IdleAnimationIDs)
+	p.DialogueTags = slices.Clone(p.DialogueTags)
+	p.XPBonusPercent = maps.Clone(p.XPBonusPercent)
+	return p
+}
+
+func copyXPConfig(cfg models.XPConfig) models.XPConfig {
+	cfg.Sources = maps.Clone(cfg.Sources)
+	return cfg
+}
+
+func copyIdempotencyRecord(record models.IdempotencyRecord) models.IdempotencyRecord {
+	record.StatusCode = clonePtr(record.StatusCode)
+	record.ResponseBody = slices.Clone(record.ResponseBody)
+	return record
+}
+
+func copyAuditEntry(entry models.AuditEntry) models.AuditEntry {
+	entry.Before = slices.Clone(entry.Before)
+	entry.After = slices.Clone(entry.After)
+	return entry
+}
@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"my-pointlings-be/internal/models"
+)
+
+func (r *Repository) ReserveIdempotencyKey(ctx context.Context, key, requestHash string, ttl time.Duration) (*models.IdempotencyRecord, bool, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	defer release()
+
+	// An expired key is forgotten so the caller can take it over.
+	current := r.now()
+	if existing, ok := r.data.idempotency[key]; ok {
+		if existing.ExpiresAt.After(current) {
+			record := copyIdempotencyRecord(existing)
+			return &record, false, nil
+		}
+		delete(r.data.idempotency, key)
+	}
+
+	record := models.IdempotencyRecord{
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   current,
+		// The TTL is stored in whole seconds, as the Postgres interval is.
+		ExpiresAt: current.Add(ttl.Truncate(time.Second)),
+	}
+	r.data.idempotency[key] = record
+	return &record, true, nil
+}
+
+func (r *Repository) GetIdempotencyKey(ctx context.Context, key string) (*models.IdempotencyRecord, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	stored, ok := r.data.idempotency[key]
+	if !ok {
+		return nil, nil
+	}
+	record := copyIdempotencyRecord(stored)
+	return &record, nil
+}
+
+func (r *Repository) SaveIdempotentResponse(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	record, ok := r.data.idempotency[key]
+	if !ok {
+		return fmt.Errorf("idempotency key not found: %s", key)
+	}
+	record.StatusCode = &statusCode
+	record.ContentType = contentType
+	record.ResponseBody = body
+	r.data.idempotency[key] = copyIdempotencyRecord(record)
+	return nil
+}
+
+func (r *Repository) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	delete(r.data.idempotency, key)
+	return nil
+}
+
+func (r *Repository) PurgeExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	current := r.now()
+	var purged int64
+	for key, record := range r.data.idempotency {
+		if !record.ExpiresAt.After(current) {
+			delete(r.data.idempotency, key)
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,121 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository"
+)
+
+func (r *Repository) PostLedgerEntry(ctx context.Context, entry *models.LedgerEntry) error {
+	if err := entry.Reason.CheckDelta(entry.Delta); err != nil {
+		return err
+	}
+	if !r.inTx {
+		return r.InTransaction(ctx, func(tx repository.API) error {
+			return tx.PostLedgerEntry(ctx, entry)
+		})
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	user, ok := r.data.users[entry.UserID]
+	if !ok {
+		return fmt.Errorf("%w: %d", models.ErrUserNotFound, entry.UserID)
+	}
+	if user.PointBalance+entry.Delta < 0 {
+		return models.ErrInsufficientBalance
+	}
+	// A reference may be posted once per reason; entries without one never clash.
+	if entry.Reference != nil {
+		for _, existing := range r.data.ledger {
+			if existing.Reason == entry.Reason && existing.Reference != nil && *existing.Reference == *entry.Reference {
+				return models.ErrDuplicateLedgerEntry
+			}
+		}
+	}
+
+	user.PointBalance += entry.Delta
+	r.data.users[user.UserID] = user
+
+	r.data.lastEntryID++
+	entry.EntryID = r.data.lastEntryID
+	entry.BalanceAfter = user.PointBalance
+	entry.CounterAccount = entry.Reason.CounterAccount()
+	entry.CreatedAt = r.now()
+	r.data.ledger = append(r.data.ledger, copyLedgerEntry(*entry))
+	return nil
+}
+
+func (r *Repository) GetLedgerEntries(ctx context.Context, userID int64, beforeID int64, limit int) ([]*models.LedgerEntry, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// Entries are appended in ID order, so walk backwards for newest first.
+	var entries []*models.LedgerEntry
+	for i := len(r.data.ledger) - 1; i >= 0 && len(entries) < limit; i-- {
+		stored := r.data.ledger[i]
+		if stored.UserID != userID || (beforeID > 0 && stored.EntryID >= beforeID) {
+			continue
+		}
+		entry := copyLedgerEntry(stored)
+		entries = append(entries, &entry)
+	}
+	return entries, nil
+}
+
+func (r *Repository) GetLedgerBalance(ctx context.Context, userID int64) (int64, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	var balance int64
+	for _, entry := range r.data.ledger {
+		if entry.UserID == userID {
+			balance += entry.Delta
+		}
+	}
+	return balance, nil
+}
+
+func (r *Repository) BackfillOpeningBalances(ctx context.Context) (int64, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	hasEntries := map[int64]bool{}
+	for _, entry := range r.data.ledger {
+		hasEntries[entry.UserID] = true
+	}
+
+	// Users that predate the ledger get one opening entry for their stored
+	// balance. Users that already have entries are left for reconciliation.
+	var backfilled int64
+	createdAt := r.now()
+	for _, user := range r.data.users {
+		if user.PointBalance <= 0 || hasEntries[user.UserID] {
+			continue
+		}
+		r.data.lastEntryID++
+		r.data.ledger = append(r.data.ledger, models.LedgerEntry{
+			EntryID:        r.data.lastEntryID,
+			UserID:         user.UserID,
+			Delta:          user.PointBalance,
+			BalanceAfter:   user.PointBalance,
+			Reason:         models.LedgerReasonOpeningBalance,
+			CounterAccount: models.LedgerReasonOpeningBalance.CounterAccount(),
+			CreatedAt:      createdAt,
+		})
+		backfilled++
+	}
+	return backfilled, nil
+}
@@ -0,0 +1,185 @@
+// Package memory implements repository.API in process memory, for tests and
+// for running the API locally without a database.
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository"
+)
+
+var _ repository.API = (*Repository)(nil)
+
+// Repository keeps every table in maps guarded by one mutex. Transactions hold
+// the mutex for their whole run, so they are serializable, and roll back by
+// restoring a copy of the tables taken when they began.
+//
+// Stored rows are never modified in place: writes replace the row, and rows
+// are copied on the way in and out, so a snapshot only needs to copy the
+// maps and slices that hold them.
+type Repository struct {
+	mu   *sync.Mutex
+	data *tables
+	inTx bool
+}
+
+type tables struct {
+	users          map[int64]models.User
+	pointlings     map[int64]pointlingRow
+	xpEvents       []models.XPEvent
+	xpConfigs      []models.XPConfig
+	personalities  map[int]models.Personality
+	items          map[int64]models.Item
+	pointlingItems map[ownership]models.PointlingItem
+	colors         map[colorKey]models.PointlingColor
+	rewards        map[int64]models.LevelReward
+	spends         map[int64]models.PointSpend
+	ledger         []models.LedgerEntry
+	reversals      map[int64]models.SpendReversal
+	idempotency    map[string]models.IdempotencyRecord
+	audit          []models.AuditEntry
+
+	// Identity sequences, mirroring the Postgres identity columns.
+	lastPointlingID int64
+	lastEventID     int64
+	lastXPConfig    int
+	lastItemID      int64
+	lastRewardID    int64
+	lastSpendID     int64
+	lastEntryID     int64
+	lastAuditID     int64
+
+	// lastNow is the last timestamp handed out by now.
+	lastNow time.Time
+	// clock replaces time.Now when set.
+	clock func() time.Time
+}
+
+type ownership struct {
+	pointlingID int64
+	itemID      int64
+}
+
+type colorKey struct {
+	pointlingID int64
+	colorHex    string
+}
+
+// New returns an empty repository.
+func New() *Repository {
+	return &Repository{
+		mu: &sync.Mutex{},
+		data: &tables{
+			users:          map[int64]models.User{},
+			pointlings:     map[int64]pointlingRow{},
+			personalities:  map[int]models.Personality{},
+			items:          map[int64]models.Item{},
+			pointlingItems: map[ownership]models.PointlingItem{},
+			colors:         map[colorKey]models.PointlingColor{},
+			rewards:        map[int64]models.LevelReward{},
+			spends:         map[int64]models.PointSpend{},
+			reversals:      map[int64]models.SpendReversal{},
+			idempotency:    map[string]models.IdempotencyRecord{},
+		},
+	}
+}
+
+// SetClock makes the repository stamp rows with times from clock, so tests
+// can place writes on a chosen day. Set it before writing anything: stamps
+// never go backwards.
+func (r *Repository) SetClock(clock func() time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.data.clock = clock
+}
+
+// SeedPersonalities adds personalities to the catalog. The catalog has no
+// write path in repository.API, so tests and local setups load it here.
+func (r *Repository) SeedPersonalities(personalities ...models.Personality) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range personalities {
+		r.data.personalities[p.PersonalityID] = copyPersonality(p)
+	}
+}
+
+func (r *Repository) InTransaction(ctx context.Context, fn func(repository.API) error) error {
+	// Nested calls join the surrounding transaction.
+	if r.inTx {
+		return fn(r)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snapshot := r.data.clone()
+	txRepo := &Repository{mu: r.mu, data: r.data, inTx: true}
+
+	err := fn(txRepo)
+	if err == nil {
+		// A cancelled request must not commit, as with a database driver.
+		err = ctx.Err()
+	}
+	if err != nil {
+		*r.data = *snapshot
+		return err
+	}
+	return nil
+}
+
+// acquire fails when ctx is done and, outside a transaction, takes the lock
+// for a single operation. Callers defer the returned release.
+func (r *Repository) acquire(ctx context.Context) (func(), error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if r.inTx {
+		return func() {}, nil
+	}
+	r.mu.Lock()
+	return r.mu.Unlock, nil
+}
+
+func (t *tables) clone() *tables {
+	c := *t
+	c.users = maps.Clone(t.users)
+	c.pointlings = maps.Clone(t.pointlings)
+	c.xpEvents = slices.Clone(t.xpEvents)
+	c.xpConfigs = slices.Clone(t.xpConfigs)
+	c.personalities = maps.Clone(t.personalities)
+	c.items = maps.Clone(t.items)
+	c.pointlingItems = maps.Clone(t.pointlingItems)
+	c.colors = maps.Clone(t.colors)
+	c.rewards = maps.Clone(t.rewards)
+	c.spends = maps.Clone(t.spends)
+	c.ledger = slices.Clone(t.ledger)
+	c.reversals = maps.Clone(t.reversals)
+	c.idempotency = maps.Clone(t.idempotency)
+	c.audit = slices.Clone(t.audit)
+	return &c
+}
+
+// now returns the current time at the microsecond precision Postgres stores,
+// so timestamps survive cursor round trips the same way in both backends.
+// It never repeats a value: writes made back to back stay ordered by time as
+// they are when each runs in its own Postgres transaction.
+func (r *Repository) now() time.Time {
+	t := time.Now()
+	if r.data.clock != nil {
+		t = r.data.clock()
+	}
+	t = t.Truncate(time.Microsecond)
+	if !t.After(r.data.lastNow) {
+		t = r.data.lastNow.Add(time.Microsecond)
+	}
+	r.data.lastNow = t
+	return t
+}
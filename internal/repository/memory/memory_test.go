@@ -0,0 +1,15 @@
+package memory_test
+
+import (
+	"testing"
+
+	"my-pointlings-be/internal/repository"
+	"my-pointlings-be/internal/repository/memory"
+	"my-pointlings-be/internal/repository/repositorytest"
+)
+
+func TestConformance(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repository.API {
+		return memory.New()
+	})
+}
@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"my-pointlings-be/internal/models"
+)
+
+func (r *Repository) ListPersonalities(ctx context.Context) ([]*models.Personality, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var personalities []*models.Personality
+	for _, stored := range r.data.personalities {
+		p := copyPersonality(stored)
+		personalities = append(personalities, &p)
+	}
+	slices.SortFunc(personalities, func(a, b *models.Personality) int {
+		return cmp.Compare(a.PersonalityID, b.PersonalityID)
+	})
+	return personalities, nil
+}
+
+func (r *Repository) GetPersonality(ctx context.Context, id int) (*models.Personality, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	stored, ok := r.data.personalities[id]
+	if !ok {
+		return nil, nil
+	}
+	p := copyPersonality(stored)
+	return &p, nil
+}
@@ -0,0 +1,655 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository"
+)
+
+// rarityOrder sorts rarities in their enum declaration order, as Postgres does.
+var rarityOrder = map[models.ItemRarity]int{
+	models.RarityCommon:    0,
+	models.RarityRare:      1,
+	models.RarityEpic:      2,
+	models.RarityLegendary: 3,
+}
+
+func (r *Repository) CreatePointling(ctx context.Context, pointling *models.Pointling) error {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if err := pointling.Look.Validate(); err != nil {
+		return err
+	}
+	if _, ok := r.data.users[pointling.UserID]; !ok {
+		return fmt.Errorf("create pointling: %w: %d", models.ErrUserNotFound, pointling.UserID)
+	}
+	if id := pointling.PersonalityID; id != nil {
+		if _, ok := r.data.personalities[*id]; !ok {
+			return fmt.Errorf("create pointling: %w: %d", models.ErrPersonalityNotFound, *id)
+		}
+	}
+
+	stored := *pointling
+	stored.PointlingID = r.data.lastPointlingID + 1
+	stored.CreatedAt = r.now()
+	row, err := newPointlingRow(stored)
+	if err != nil {
+		return fmt.Errorf("marshal look_json: %w", err)
+	}
+	r.data.lastPointlingID++
+	r.data.pointlings[stored.PointlingID] = row
+
+	pointling.PointlingID = stored.PointlingID
+	pointling.CreatedAt = stored.CreatedAt
+	return nil
+}
+
+func (r *Repository) GetPointlingByID(ctx context.Context, id int64) (*models.Pointling, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return r.pointling(id)
+}
+
+func (r *Repository) LockPointling(ctx context.Context, id int64) (*models.Pointling, error) {
+	if !r.inTx {
+		return nil, fmt.Errorf("lock pointling %d: must be called inside InTransaction", id)
+	}
+	// The transaction already holds the store lock.
+	return r.GetPointlingByID(ctx, id)
+}
+
+func (r *Repository) pointling(id int64) (*models.Pointling, error) {
+	row, ok := r.data.pointlings[id]
+	if !ok {
+		return nil, nil
+	}
+	pointling, err := row.toModel()
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal look_json: %w", err)
+	}
+	return pointling, nil
+}
+
+func (r *Repository) GetPointlingByUserID(ctx context.Context, userID int64) ([]*models.Pointling, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var pointlings []*models.Pointling
+	for _, row := range r.data.pointlings {
+		if row.pointling.UserID != userID {
+			continue
+		}
+		pointling, err := row.toModel()
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal look_json: %w", err)
+		}
+		pointlings = append(pointlings, pointling)
+	}
+	slices.SortFunc(pointlings, func(a, b *models.Pointling) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.PointlingID, a.PointlingID))
+	})
+	return pointlings, nil
+}
+
+func (r *Repository) UpdatePointlingLook(ctx context.Context, id int64, look models.Look) error {
+	if err := look.Validate(); err != nil {
+		return err
+	}
+	return r.updatePointling(ctx, id, func(p *models.Pointling) {
+		p.Look = look
+	})
+}
+
+func (r *Repository) UpdatePointlingXP(ctx context.Context, id int64, currentXP, requiredXP int) error {
+	return r.updatePointling(ctx, id, func(p *models.Pointling) {
+		p.CurrentXP = currentXP
+		p.RequiredXP = requiredXP
+	})
+}
+
+func (r *Repository) UpdatePointlingLevel(ctx context.Context, id int64, level int) error {
+	return r.updatePointling(ctx, id, func(p *models.Pointling) {
+		p.Level = level
+	})
+}
+
+func (r *Repository) UpdatePointlingNickname(ctx context.Context, id int64, nickname *string) error {
+	return r.updatePointling(ctx, id, func(p *models.Pointling) {
+		p.Nickname = nickname
+	})
+}
+
+// updatePointling applies update to a copy of the stored pointling and
+// writes it back.
+func (r *Repository) updatePointling(ctx context.Context, id int64, update func(*models.Pointling)) error {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	pointling, err := r.pointling(id)
+	if err != nil {
+		return err
+	}
+	if pointling == nil {
+		return fmt.Errorf("%w: %d", models.ErrPointlingNotFound, id)
+	}
+	update(pointling)
+	row, err := newPointlingRow(*pointling)
+	if err != nil {
+		return fmt.Errorf("marshal look_json: %w", err)
+	}
+	r.data.pointlings[id] = row
+	return nil
+}
+
+func (r *Repository) GetUser(ctx context.Context, userID int64) (*models.User, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	user, ok := r.data.users[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &user, nil
+}
+
+func (r *Repository) CreateUser(ctx context.Context, user *models.User) error {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if user.Timezone == "" {
+		user.Timezone = models.DefaultTimezone
+	}
+	if _, ok := r.data.users[user.UserID]; ok {
+		return fmt.Errorf("create user: user %d already exists", user.UserID)
+	}
+
+	stored := *user
+	stored.CreatedAt = r.now()
+	r.data.users[user.UserID] = stored
+	return nil
+}
+
+func (r *Repository) UpdateUserTimezone(ctx context.Context, userID int64, timezone string) error {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	user, ok := r.data.users[userID]
+	if !ok {
+		return fmt.Errorf("%w: %d", models.ErrUserNotFound, userID)
+	}
+	user.Timezone = timezone
+	r.data.users[userID] = user
+	return nil
+}
+
+func (r *Repository) ListUsers(ctx context.Context, limit, offset int) ([]*models.User, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var users []*models.User
+	for _, user := range r.data.users {
+		users = append(users, &user)
+	}
+	slices.SortFunc(users, func(a, b *models.User) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.UserID, a.UserID))
+	})
+	return limitSlice(offsetSlice(users, offset), limit), nil
+}
+
+func (r *Repository) AddXP(ctx context.Context, event *models.XPEvent, limit models.XPDailyCap) error {
+	// The cap check must see every committed gain, so run as one transaction.
+	if !r.inTx {
+		return r.InTransaction(ctx, func(tx repository.API) error {
+			return tx.AddXP(ctx, event, limit)
+		})
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if _, ok := r.data.pointlings[event.PointlingID]; !ok {
+		return fmt.Errorf("%w: %d", models.ErrPointlingNotFound, event.PointlingID)
+	}
+
+	currentDaily := r.dailyXPBySource(event.PointlingID, event.Source, limit.Window)
+	remaining := limit.Max - currentDaily
+	if remaining <= 0 {
+		return models.ErrDailyXPLimitReached
+	}
+	event.XPAmount = min(event.XPAmount, remaining)
+
+	r.data.lastEventID++
+	event.EventID = r.data.lastEventID
+	event.EventTS = r.now()
+	r.data.xpEvents = append(r.data.xpEvents, *event)
+	return nil
+}
+
+func (r *Repository) GetEventsByPointling(ctx context.Context, filter models.XPHistoryFilter) ([]*models.XPEvent, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var events []*models.XPEvent
+	for _, event := range r.data.xpEvents {
+		if !matchesXPHistoryFilter(event, filter) {
+			continue
+		}
+		if c := filter.Cursor; c != nil && !before(event.EventTS, event.EventID, c.TS, c.ID) {
+			continue
+		}
+		events = append(events, &event)
+	}
+	slices.SortFunc(events, func(a, b *models.XPEvent) int {
+		return cmp.Or(b.EventTS.Compare(a.EventTS), cmp.Compare(b.EventID, a.EventID))
+	})
+	return limitSlice(events, filter.Limit), nil
+}
+
+func (r *Repository) GetDailyXPTotals(ctx context.Context, filter models.XPHistoryFilter) ([]*models.XPDailyTotal, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	timeZone := filter.TimeZone
+	if timeZone == "" {
+		timeZone = "UTC"
+	}
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("query daily xp totals: %w", err)
+	}
+
+	type dayKey struct {
+		day    string
+		source models.XPEventSource
+	}
+	byDay := map[dayKey]*models.XPDailyTotal{}
+	var totals []*models.XPDailyTotal
+	for _, event := range r.data.xpEvents {
+		if !matchesXPHistoryFilter(event, filter) {
+			continue
+		}
+		key := dayKey{day: event.EventTS.In(loc).Format(time.DateOnly), source: event.Source}
+		total, ok := byDay[key]
+		if !ok {
+			total = &models.XPDailyTotal{Day: key.day, Source: key.source}
+			byDay[key] = total
+			totals = append(totals, total)
+		}
+		total.TotalXP += event.XPAmount
+		total.EventCount++
+	}
+	slices.SortFunc(totals, func(a, b *models.XPDailyTotal) int {
+		return cmp.Or(strings.Compare(b.Day, a.Day), strings.Compare(string(a.Source), string(b.Source)))
+	})
+	return totals, nil
+}
+
+func matchesXPHistoryFilter(event models.XPEvent, filter models.XPHistoryFilter) bool {
+	if event.PointlingID != filter.PointlingID {
+		return false
+	}
+	if filter.Source != nil && event.Source != *filter.Source {
+		return false
+	}
+	return filter.Contains(event.EventTS)
+}
+
+func (r *Repository) GetDailyXPBySource(ctx context.Context, pointlingID int64, source models.XPEventSource, window models.DayWindow) (int, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	return r.dailyXPBySource(pointlingID, source, window), nil
+}
+
+func (r *Repository) dailyXPBySource(pointlingID int64, source models.XPEventSource, window models.DayWindow) int {
+	var total int
+	for _, event := range r.data.xpEvents {
+		if event.PointlingID == pointlingID && event.Source == source &&
+			!event.EventTS.Before(window.Start) && event.EventTS.Before(window.End) {
+			total += event.XPAmount
+		}
+	}
+	return total
+}
+
+func (r *Repository) AddItem(ctx context.Context, pointlingID, itemID int64) error {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if _, ok := r.data.pointlings[pointlingID]; !ok {
+		return fmt.Errorf("add item: %w: %d", models.ErrPointlingNotFound, pointlingID)
+	}
+	if _, ok := r.data.items[itemID]; !ok {
+		return fmt.Errorf("add item: %w: %d", models.ErrItemNotFound, itemID)
+	}
+	key := ownership{pointlingID: pointlingID, itemID: itemID}
+	if _, ok := r.data.pointlingItems[key]; ok {
+		return models.ErrAlreadyOwned
+	}
+	r.data.pointlingItems[key] = models.PointlingItem{
+		PointlingID: pointlingID,
+		ItemID:      itemID,
+		AcquiredAt:  r.now(),
+	}
+	return nil
+}
+
+func (r *Repository) GetItems(ctx context.Context, pointlingID int64, equipped *bool) ([]*models.PointlingItem, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var items []*models.PointlingItem
+	for key, pi := range r.data.pointlingItems {
+		if key.pointlingID != pointlingID || (equipped != nil && pi.Equipped != *equipped) {
+			continue
+		}
+		items = append(items, r.withItem(pi))
+	}
+	slices.SortFunc(items, func(a, b *models.PointlingItem) int {
+		return cmp.Or(b.AcquiredAt.Compare(a.AcquiredAt), cmp.Compare(b.ItemID, a.ItemID))
+	})
+	return items, nil
+}
+
+// withItem returns a copy of pi with its catalog item joined in.
+func (r *Repository) withItem(pi models.PointlingItem) *models.PointlingItem {
+	item := copyItem(r.data.items[pi.ItemID])
+	pi.Item = &item
+	return &pi
+}
+
+func (r *Repository) GetEquippedInSlot(ctx context.Context, pointlingID int64, slot models.ItemSlot) (*models.PointlingItem, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	for key, pi := range r.data.pointlingItems {
+		if key.pointlingID != pointlingID || !pi.Equipped {
+			continue
+		}
+		if s := r.data.items[key.itemID].Slot; s != nil && *s == slot {
+			return r.withItem(pi), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *Repository) ToggleEquipped(ctx context.Context, pointlingID, itemID int64, equipped bool) error {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	key := ownership{pointlingID: pointlingID, itemID: itemID}
+	target, ok := r.data.pointlingItems[key]
+	if !ok {
+		return models.ErrItemNotOwned
+	}
+
+	if equipped {
+		// If equipping, first unequip any item in the same slot
+		if slot := r.data.items[itemID].Slot; slot != nil {
+			for k, pi := range r.data.pointlingItems {
+				s := r.data.items[k.itemID].Slot
+				if k.pointlingID == pointlingID && pi.Equipped && s != nil && *s == *slot {
+					pi.Equipped = false
+					r.data.pointlingItems[k] = pi
+				}
+			}
+		}
+	}
+
+	target.Equipped = equipped
+	r.data.pointlingItems[key] = target
+	return nil
+}
+
+func (r *Repository) CreatePointSpend(ctx context.Context, spend *models.PointSpend) error {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if _, ok := r.data.users[spend.UserID]; !ok {
+		return fmt.Errorf("create spend record: %w: %d", models.ErrUserNotFound, spend.UserID)
+	}
+	if _, ok := r.data.items[spend.ItemID]; !ok {
+		return fmt.Errorf("create spend record: %w: %d", models.ErrItemNotFound, spend.ItemID)
+	}
+	if id := spend.PointlingID; id != nil {
+		if _, ok := r.data.pointlings[*id]; !ok {
+			return fmt.Errorf("create spend record: %w: %d", models.ErrPointlingNotFound, *id)
+		}
+	}
+
+	r.data.lastSpendID++
+	spend.SpendID = r.data.lastSpendID
+	spend.SpendTS = r.now()
+	r.data.spends[spend.SpendID] = copySpend(*spend)
+	return nil
+}
+
+func (r *Repository) GetByUser(ctx context.Context, filter models.SpendHistoryFilter) ([]*models.PointSpend, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var spends []*models.PointSpend
+	for _, stored := range r.data.spends {
+		if stored.UserID != filter.UserID {
+			continue
+		}
+		if !filter.Contains(stored.SpendTS) {
+			continue
+		}
+		if c := filter.Cursor; c != nil && !before(stored.SpendTS, stored.SpendID, c.TS, c.ID) {
+			continue
+		}
+		spend := copySpend(stored)
+		item := copyItem(r.data.items[spend.ItemID])
+		spend.Item = &item
+		spends = append(spends, &spend)
+	}
+	slices.SortFunc(spends, func(a, b *models.PointSpend) int {
+		return cmp.Or(b.SpendTS.Compare(a.SpendTS), cmp.Compare(b.SpendID, a.SpendID))
+	})
+	return limitSlice(offsetSlice(spends, filter.Offset), filter.Limit), nil
+}
+
+func (r *Repository) GetTotalSpentByUser(ctx context.Context, userID int64, period models.TimeRange) (int64, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	var total int64
+	for _, spend := range r.data.spends {
+		if spend.UserID == userID && spend.ReversedAt == nil && period.Contains(spend.SpendTS) {
+			total += int64(spend.PointsSpent)
+		}
+	}
+	return total, nil
+}
+
+func (r *Repository) SpendPoints(ctx context.Context, spend *models.PointSpend) error {
+	if !r.inTx {
+		return r.InTransaction(ctx, func(tx repository.API) error {
+			return tx.SpendPoints(ctx, spend)
+		})
+	}
+
+	// Create the spend record first so the debit can reference it
+	if err := r.CreatePointSpend(ctx, spend); err != nil {
+		return err
+	}
+
+	reference := models.SpendReference(spend.SpendID)
+	return r.PostLedgerEntry(ctx, &models.LedgerEntry{
+		UserID:    spend.UserID,
+		Delta:     -int64(spend.PointsSpent),
+		Reason:    models.LedgerReasonSpend,
+		Reference: &reference,
+	})
+}
+
+func (r *Repository) GetItemByID(ctx context.Context, id int64) (*models.Item, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	stored, ok := r.data.items[id]
+	if !ok {
+		return nil, nil
+	}
+	item := copyItem(stored)
+	return &item, nil
+}
+
+func (r *Repository) ListItems(ctx context.Context, category *models.ItemCategory, rarity *models.ItemRarity, slot *models.ItemSlot) ([]*models.Item, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var items []*models.Item
+	for _, stored := range r.data.items {
+		if category != nil && stored.Category != *category {
+			continue
+		}
+		if rarity != nil && stored.Rarity != *rarity {
+			continue
+		}
+		if slot != nil && (stored.Slot == nil || *stored.Slot != *slot) {
+			continue
+		}
+		item := copyItem(stored)
+		items = append(items, &item)
+	}
+	slices.SortFunc(items, func(a, b *models.Item) int {
+		return cmp.Or(strings.Compare(a.Name, b.Name), cmp.Compare(a.ItemID, b.ItemID))
+	})
+	return items, nil
+}
+
+func (r *Repository) GetUnlocksForLevel(ctx context.Context, level int) ([]*models.Item, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var items []*models.Item
+	for _, stored := range r.data.items {
+		if stored.UnlockLevel == nil || *stored.UnlockLevel != level {
+			continue
+		}
+		item := copyItem(stored)
+		items = append(items, &item)
+	}
+	slices.SortFunc(items, func(a, b *models.Item) int {
+		return cmp.Or(
+			cmp.Compare(rarityOrder[a.Rarity], rarityOrder[b.Rarity]),
+			strings.Compare(a.Name, b.Name),
+			cmp.Compare(a.ItemID, b.ItemID),
+		)
+	})
+	return items, nil
+}
+
+func (r *Repository) CreateItem(ctx context.Context, item *models.Item) error {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	r.data.lastItemID++
+	item.ItemID = r.data.lastItemID
+	r.data.items[item.ItemID] = copyItem(*item)
+	return nil
+}
+
+// before reports whether (ts, id) sorts before the cursor (cursorTS, cursorID),
+// matching a Postgres row comparison.
+func before(ts time.Time, id int64, cursorTS time.Time, cursorID int64) bool {
+	return ts.Before(cursorTS) || (ts.Equal(cursorTS) && id < cursorID)
+}
+
+// limitSlice applies a SQL LIMIT; a non-positive limit returns no rows, as
+// LIMIT 0 does.
+func limitSlice[T any](rows []T, limit int) []T {
+	if limit <= 0 {
+		return nil
+	}
+	if len(rows) > limit {
+		return rows[:limit]
+	}
+	return rows
+}
+
+func offsetSlice[T any](rows []T, offset int) []T {
+	if offset <= 0 {
+		return rows
+	}
+	if offset >= len(rows) {
+		return nil
+	}
+	return rows[offset:]
+}
@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"my-pointlings-be/internal/models"
+)
+
+func (r *Repository) LockPointSpend(ctx context.Context, spendID int64) (*models.PointSpend, error) {
+	if !r.inTx {
+		return nil, fmt.Errorf("lock point spend %d: must be called inside InTransaction", spendID)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	stored, ok := r.data.spends[spendID]
+	if !ok {
+		return nil, nil
+	}
+	spend := copySpend(stored)
+	return &spend, nil
+}
+
+func (r *Repository) MarkSpendReversed(ctx context.Context, spendID int64) error {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	spend, ok := r.data.spends[spendID]
+	if !ok || spend.ReversedAt != nil {
+		return fmt.Errorf("%w: %d", models.ErrSpendNotFound, spendID)
+	}
+	reversedAt := r.now()
+	spend.ReversedAt = &reversedAt
+	r.data.spends[spendID] = spend
+	return nil
+}
+
+func (r *Repository) RemoveItem(ctx context.Context, pointlingID, itemID int64) (bool, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer release()
+
+	key := ownership{pointlingID: pointlingID, itemID: itemID}
+	if _, ok := r.data.pointlingItems[key]; !ok {
+		return false, nil
+	}
+	delete(r.data.pointlingItems, key)
+	return true, nil
+}
+
+func (r *Repository) CreateSpendReversal(ctx context.Context, reversal *models.SpendReversal) error {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if _, ok := r.data.spends[reversal.SpendID]; !ok {
+		return fmt.Errorf("create spend reversal: %w: %d", models.ErrSpendNotFound, reversal.SpendID)
+	}
+	if _, ok := r.data.reversals[reversal.SpendID]; ok {
+		return fmt.Errorf("create spend reversal: spend %d already has a reversal", reversal.SpendID)
+	}
+	reversal.ReversedAt = r.now()
+	r.data.reversals[reversal.SpendID] = copyReversal(*reversal)
+	return nil
+}
+
+func (r *Repository) GetSpendReversal(ctx context.Context, spendID int64) (*models.SpendReversal, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	stored, ok := r.data.reversals[spendID]
+	if !ok {
+		return nil, nil
+	}
+	reversal := copyReversal(stored)
+	return &reversal, nil
+}
@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"my-pointlings-be/internal/models"
+)
+
+func (r *Repository) CreateLevelReward(ctx context.Context, reward *models.LevelReward) error {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if _, ok := r.data.pointlings[reward.PointlingID]; !ok {
+		return fmt.Errorf("create level reward: %w: %d", models.ErrPointlingNotFound, reward.PointlingID)
+	}
+	for _, existing := range r.data.rewards {
+		if existing.PointlingID == reward.PointlingID && existing.Level == reward.Level {
+			return fmt.Errorf("create level reward: level %d already offered to pointling %d", reward.Level, reward.PointlingID)
+		}
+	}
+
+	r.data.lastRewardID++
+	reward.RewardID = r.data.lastRewardID
+	reward.OfferedAt = r.now()
+	r.data.rewards[reward.RewardID] = copyReward(*reward)
+	return nil
+}
+
+func (r *Repository) GetLevelReward(ctx context.Context, rewardID int64) (*models.LevelReward, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	stored, ok := r.data.rewards[rewardID]
+	if !ok {
+		return nil, nil
+	}
+	reward := copyReward(stored)
+	return &reward, nil
+}
+
+func (r *Repository) GetPendingRewards(ctx context.Context, pointlingID int64) ([]*models.LevelReward, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	current := r.now()
+	var rewards []*models.LevelReward
+	for _, stored := range r.data.rewards {
+		if stored.PointlingID != pointlingID || stored.ClaimedAt != nil || !stored.ExpiresAt.After(current) {
+			continue
+		}
+		reward := copyReward(stored)
+		rewards = append(rewards, &reward)
+	}
+	slices.SortFunc(rewards, func(a, b *models.LevelReward) int {
+		return cmp.Compare(a.Level, b.Level)
+	})
+	return rewards, nil
+}
+
+func (r *Repository) ClaimLevelReward(ctx context.Context, rewardID int64, option models.RewardOption) error {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	// Only an open, unexpired offer can be claimed; a second choice matches nothing.
+	claimedAt := r.now()
+	reward, ok := r.data.rewards[rewardID]
+	if !ok || reward.ClaimedAt != nil || !reward.ExpiresAt.After(claimedAt) {
+		return models.ErrRewardAlreadyClaimed
+	}
+	reward.ChosenOption = &option
+	reward.ClaimedAt = &claimedAt
+	r.data.rewards[rewardID] = reward
+	return nil
+}
+
+func (r *Repository) AddPointlingColor(ctx context.Context, pointlingID int64, colorHex string) error {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if _, ok := r.data.pointlings[pointlingID]; !ok {
+		return fmt.Errorf("add color: %w: %d", models.ErrPointlingNotFound, pointlingID)
+	}
+	key := colorKey{pointlingID: pointlingID, colorHex: colorHex}
+	if _, ok := r.data.colors[key]; ok {
+		return models.ErrAlreadyOwned
+	}
+	r.data.colors[key] = models.PointlingColor{
+		PointlingID: pointlingID,
+		ColorHex:    colorHex,
+		AcquiredAt:  r.now(),
+	}
+	return nil
+}
+
+func (r *Repository) GetPointlingColors(ctx context.Context, pointlingID int64) ([]*models.PointlingColor, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var colors []*models.PointlingColor
+	for key, color := range r.data.colors {
+		if key.pointlingID == pointlingID {
+			colors = append(colors, &color)
+		}
+	}
+	slices.SortFunc(colors, func(a, b *models.PointlingColor) int {
+		return cmp.Or(a.AcquiredAt.Compare(b.AcquiredAt), cmp.Compare(a.ColorHex, b.ColorHex))
+	})
+	return colors, nil
+}
@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"context"
+
+	"my-pointlings-be/internal/models"
+)
+
+func (r *Repository) GetActiveXPConfig(ctx context.Context) (*models.XPConfig, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if len(r.data.xpConfigs) == 0 {
+		return nil, nil
+	}
+	cfg := copyXPConfig(r.data.xpConfigs[len(r.data.xpConfigs)-1])
+	return &cfg, nil
+}
+
+func (r *Repository) ListXPConfigVersions(ctx context.Context, limit int) ([]*models.XPConfig, error) {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// Versions are appended in order, so walk backwards for newest first.
+	var configs []*models.XPConfig
+	for i := len(r.data.xpConfigs) - 1; i >= 0 && len(configs) < limit; i-- {
+		cfg := copyXPConfig(r.data.xpConfigs[i])
+		configs = append(configs, &cfg)
+	}
+	return configs, nil
+}
+
+func (r *Repository) CreateXPConfigVersion(ctx context.Context, cfg *models.XPConfig) error {
+	release, err := r.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	r.data.lastXPConfig++
+	cfg.Version = r.data.lastXPConfig
+	cfg.CreatedAt = r.now()
+	r.data.xpConfigs = append(r.data.xpConfigs, copyXPConfig(*cfg))
+	return nil
+}
@@ -0,0 +1,232 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"my-pointlings-be/internal/database"
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypedLooksMigration(t *testing.T) {
+	db := testServer.Open(t)
+	ctx := context.Background()
+	_, err := db.ExecContext(ctx, "TRUNCATE "+appTables+" RESTART IDENTITY CASCADE")
+	require.NoError(t, err)
+	repo := repository.New(db)
+
+	require.NoError(t, repo.CreateUser(ctx, &models.User{UserID: 1, DisplayName: "one"}))
+	p := models.NewPointling(1, nil)
+	require.NoError(t, repo.CreatePointling(ctx, p))
+	hat := models.SlotHat
+	beret := &models.Item{Name: "Beret", Category: models.CategoryAccessory, Slot: &hat, AssetID: "beret", Rarity: models.RarityCommon}
+	glow := &models.Item{Name: "Glow", Category: models.CategoryFeature, AssetID: "glow", Rarity: models.RarityRare}
+	require.NoError(t, repo.CreateItem(ctx, beret))
+	require.NoError(t, repo.CreateItem(ctx, glow))
+	require.NoError(t, repo.AddItem(ctx, p.PointlingID, beret.ItemID))
+	require.NoError(t, repo.AddItem(ctx, p.PointlingID, glow.ItemID))
+	require.NoError(t, repo.ToggleEquipped(ctx, p.PointlingID, beret.ItemID, true))
+
+	migrateDownTo(t, db, 4)
+	_, err = db.ExecContext(ctx,
+		`UPDATE public.pointlings SET look_json = '{"base_body": "Big Body!", "color": "#ff8fa3", "hat": "beret"}' WHERE pointling_id = $1`,
+		p.PointlingID)
+	require.NoError(t, err)
+	_, err = database.MigrateUp(ctx, db)
+	require.NoError(t, err)
+
+	typed := `{"base_body": "classic", "color": "#FF8FA3", "slots": {"HAT": "beret"}, "features": ["glow"]}`
+	require.JSONEq(t, typed, rawLook(t, db, p.PointlingID))
+
+	// Down restores the free-form shape, and up again gives the same look.
+	migrateDownTo(t, db, 4)
+	require.JSONEq(t, `{"base_body": "classic", "color": "#FF8FA3", "hat": "beret", "features": ["glow"]}`,
+		rawLook(t, db, p.PointlingID))
+	_, err = database.MigrateUp(ctx, db)
+	require.NoError(t, err)
+	require.JSONEq(t, typed, rawLook(t, db, p.PointlingID))
+}
+
+func rawLook(t *testing.T, db *sql.DB, pointlingID int64) string {
+	t.Helper()
+	var raw string
+	require.NoError(t, db.QueryRowContext(context.Background(),
+		`SELECT look_json::text FROM public.pointlings WHERE pointling_id = $1`, pointlingID).Scan(&raw))
+	return raw
+}
+
+func TestMigrationsRoundTrip(t *testing.T) {
+	db := testServer.Open(t)
+	ctx := context.Background()
+	migrations, err := database.Migrations()
+	require.NoError(t, err)
+
+	migrateDownTo(t, db, 0)
+	var tables int
+	require.NoError(t, db.QueryRowContext(ctx,
+		`SELECT count(*) FROM pg_tables WHERE schemaname = 'public' AND tablename <> 'schema_migrations'`).Scan(&tables))
+	require.Zero(t, tables, "down migrations left tables behind")
+	var types int
+	require.NoError(t, db.QueryRowContext(ctx,
+		`SELECT count(*) FROM pg_type t JOIN pg_namespace n ON n.oid = t.typnamespace
+		 WHERE n.nspname = 'public' AND t.typtype = 'e'`).Scan(&types))
+	require.Zero(t, types, "down migrations left enum types behind")
+
+	applied, err := database.MigrateUp(ctx, db)
+	require.NoError(t, err)
+	require.Len(t, applied, len(migrations))
+
+	personalities, err := repository.New(db).ListPersonalities(ctx)
+	require.NoError(t, err)
+	var got []models.Personality
+	for _, p := range personalities {
+		got = append(got, *p)
+	}
+	require.Equal(t, models.StarterPersonalities(), got)
+}
+
+// legacySchema is the schema production ran before migrations existed, with
+// a few rows in it.
+const legacySchema = `
+	CREATE TYPE item_category AS ENUM ('ACCESSORY', 'FEATURE');
+	CREATE TYPE item_slot AS ENUM ('HAT', 'SHOES', 'FACE', 'WINGS');
+	CREATE TYPE item_rarity AS ENUM ('COMMON', 'RARE', 'EPIC', 'LEGENDARY');
+	CREATE TYPE xp_event_source AS ENUM ('RECEIPT', 'PLAY', 'DAILY');
+
+	CREATE TABLE public.users (
+	  user_id bigint PRIMARY KEY,
+	  display_name text NOT NULL,
+	  point_balance bigint NOT NULL DEFAULT 0,
+	  created_at timestamp with time zone NOT NULL DEFAULT now()
+	);
+	CREATE TABLE public.pointlings (
+	  pointling_id bigserial PRIMARY KEY,
+	  user_id bigint NOT NULL REFERENCES public.users(user_id),
+	  nickname text,
+	  level integer NOT NULL DEFAULT 1,
+	  current_xp integer NOT NULL DEFAULT 0,
+	  required_xp integer NOT NULL DEFAULT 3,
+	  personality_id integer,
+	  look_json jsonb NOT NULL DEFAULT '{}'::jsonb,
+	  created_at timestamp with time zone NOT NULL DEFAULT now()
+	);
+	CREATE TABLE public.items (
+	  item_id bigserial PRIMARY KEY,
+	  item_category item_category NOT NULL,
+	  slot item_slot,
+	  asset_id text NOT NULL,
+	  name text NOT NULL,
+	  rarity item_rarity NOT NULL,
+	  price_points integer,
+	  unlock_level integer
+	);
+	CREATE TABLE public.pointling_items (
+	  pointling_id bigint NOT NULL REFERENCES public.pointlings(pointling_id),
+	  item_id bigint NOT NULL REFERENCES public.items(item_id),
+	  acquired_at timestamp with time zone NOT NULL DEFAULT now(),
+	  equipped boolean NOT NULL DEFAULT false,
+	  PRIMARY KEY (pointling_id, item_id)
+	);
+	CREATE TABLE public.pointling_colors (
+	  pointling_id bigint NOT NULL REFERENCES public.pointlings(pointling_id),
+	  color_hex character(7) NOT NULL,
+	  acquired_at timestamp with time zone NOT NULL DEFAULT now(),
+	  PRIMARY KEY (pointling_id, color_hex)
+	);
+	CREATE TABLE public.point_spend (
+	  spend_id bigserial PRIMARY KEY,
+	  user_id bigint NOT NULL REFERENCES public.users(user_id),
+	  item_id bigint NOT NULL REFERENCES public.items(item_id),
+	  points_spent integer NOT NULL CHECK (points_spent > 0),
+	  spend_ts timestamp with time zone NOT NULL DEFAULT now()
+	);
+	CREATE TABLE public.xp_events (
+	  event_id bigserial PRIMARY KEY,
+	  pointling_id bigint NOT NULL REFERENCES public.pointlings(pointling_id),
+	  source xp_event_source NOT NULL,
+	  xp_amount integer NOT NULL,
+	  event_ts timestamp with time zone NOT NULL DEFAULT now()
+	);
+
+	INSERT INTO public.users (user_id, display_name, point_balance) VALUES (1, 'one', 40);
+	INSERT INTO public.pointlings (user_id, nickname, personality_id, look_json)
+	VALUES (1, 'Old', 7, '{"base_body": "Big Body!", "color": "#ff8fa3", "hat": "beret"}');
+	INSERT INTO public.items (item_category, slot, asset_id, name, rarity, price_points)
+	VALUES ('ACCESSORY', 'HAT', 'beret', 'Beret', 'COMMON', 10);
+	INSERT INTO public.pointling_items (pointling_id, item_id, equipped) VALUES (1, 1, true);
+	INSERT INTO public.point_spend (user_id, item_id, points_spent) VALUES (1, 1, 10);
+	INSERT INTO public.xp_events (pointling_id, source, xp_amount) VALUES (1, 'PLAY', 2);`
+
+func TestMigrationsAdoptExistingSchema(t *testing.T) {
+	db := testServer.Open(t)
+	ctx := context.Background()
+	migrations, err := database.Migrations()
+	require.NoError(t, err)
+
+	migrateDownTo(t, db, 0)
+	t.Cleanup(func() {
+		// Hand the remaining tests a database built by the migrations alone.
+		migrateDownTo(t, db, 0)
+		_, err := db.ExecContext(ctx, `DROP TYPE xp_event_source`)
+		require.NoError(t, err)
+		_, err = database.MigrateUp(ctx, db)
+		require.NoError(t, err)
+	})
+	_, err = db.ExecContext(ctx, legacySchema)
+	require.NoError(t, err)
+
+	applied, err := database.MigrateUp(ctx, db)
+	require.NoError(t, err)
+	require.Len(t, applied, len(migrations))
+
+	repo := repository.New(db)
+	user, err := repo.GetUser(ctx, 1)
+	require.NoError(t, err)
+	require.EqualValues(t, 40, user.PointBalance)
+	require.Equal(t, "UTC", user.Timezone)
+
+	p, err := repo.GetPointlingByID(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, "Old", *p.Nickname)
+	require.Nil(t, p.PersonalityID)
+	require.Equal(t, models.Look{BaseBody: "classic", Color: "#FF8FA3", Slots: map[models.ItemSlot]string{models.SlotHat: "beret"}}, p.Look)
+
+	items, err := repo.GetItems(ctx, 1, nil)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, models.CategoryAccessory, items[0].Item.Category)
+
+	spends, err := repo.GetByUser(ctx, models.SpendHistoryFilter{UserID: 1, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, spends, 1)
+	require.Nil(t, spends[0].PointlingID)
+
+	// Sources outside the old enum are accepted now that the column is text.
+	require.NoError(t, repo.AddXP(ctx, &models.XPEvent{PointlingID: 1, Source: "QUEST", XPAmount: 3},
+		models.XPDailyCap{Window: models.DailyWindow(time.Now(), time.UTC), Max: 100}))
+
+	personalities, err := repo.ListPersonalities(ctx)
+	require.NoError(t, err)
+	require.Len(t, personalities, len(models.StarterPersonalities()))
+}
+
+// migrateDownTo rolls back every applied migration newer than version.
+func migrateDownTo(t *testing.T, db *sql.DB, version int64) {
+	t.Helper()
+	ctx := context.Background()
+	statuses, err := database.MigrationStatuses(ctx, db)
+	require.NoError(t, err)
+	steps := 0
+	for _, s := range statuses {
+		if s.Version > version && s.AppliedAt != nil {
+			steps++
+		}
+	}
+	_, err = database.MigrateDown(ctx, db, steps)
+	require.NoError(t, err)
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"my-pointlings-be/internal/models"
+)
+
+func (r *PointlingRepository) ListPersonalities(ctx context.Context) ([]*models.Personality, error) {
+	query := `
+		SELECT personality_id, code, name, traits, idle_animation_ids,
+			dialogue_tags, weight, xp_bonus_percent
+		FROM public.personalities
+		ORDER BY personality_id ASC`
+
+	rows, err := r.txWrapper().QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list personalities query: %w", err)
+	}
+	defer rows.Close()
+
+	var personalities []*models.Personality
+	for rows.Next() {
+		p, err := scanPersonality(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan personality: %w", err)
+		}
+		personalities = append(personalities, p)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate personalities: %w", err)
+	}
+	return personalities, nil
+}
+
+func (r *PointlingRepository) GetPersonality(ctx context.Context, id int) (*models.Personality, error) {
+	query := `
+		SELECT personality_id, code, name, traits, idle_animation_ids,
+			dialogue_tags, weight, xp_bonus_percent
+		FROM public.personalities
+		WHERE personality_id = $1`
+
+	p, err := scanPersonality(r.txWrapper().QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get personality: %w", err)
+	}
+	return p, nil
+}
+
+func scanPersonality(row rowScanner) (*models.Personality, error) {
+	p := &models.Personality{}
+	var traits, animations, dialogue, bonuses []byte
+
+	err := row.Scan(
+		&p.PersonalityID,
+		&p.Code,
+		&p.Name,
+		&traits,
+		&animations,
+		&dialogue,
+		&p.Weight,
+		&bonuses,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, field := range []struct {
+		raw  []byte
+		dest interface{}
+	}{
+		{traits, &p.Traits},
+		{animations, &p.IdleAnimationIDs},
+		{dialogue, &p.DialogueTags},
+		{bonuses, &p.XPBonusPercent},
+	} {
+		if err := json.Unmarshal(field.raw, field.dest); err != nil {
+			return nil, fmt.Errorf("unmarshal personality %d: %w", p.PersonalityID, err)
+		}
+	}
+	return p, nil
+}
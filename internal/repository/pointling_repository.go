@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"my-pointlings-be/internal/models"
 )
@@ -17,83 +18,179 @@ type PointlingRepository struct {
 }
 
 type API interface {
-	// InTransaction executes operations in a transaction
-	InTransaction(fn func(*PointlingRepository) error) error
+	// InTransaction executes operations in a transaction; called on a
+	// repository already in one, it runs fn in that same transaction
+	InTransaction(ctx context.Context, fn func(API) error) error
 
 	// Create stores a new pointling
-	CreatePointling(pointling *models.Pointling) error
+	CreatePointling(ctx context.Context, pointling *models.Pointling) error
 
 	// GetByID retrieves a pointling by its ID
-	GetPointlingByID(id int64) (*models.Pointling, error)
+	GetPointlingByID(ctx context.Context, id int64) (*models.Pointling, error)
+
+	// LockPointling retrieves a pointling and holds its row lock until the
+	// surrounding transaction ends; it must be called inside InTransaction
+	LockPointling(ctx context.Context, id int64) (*models.Pointling, error)
 
 	// GetByUserID retrieves all pointlings owned by a user
-	GetPointlingByUserID(userID int64) ([]*models.Pointling, error)
+	GetPointlingByUserID(ctx context.Context, userID int64) ([]*models.Pointling, error)
 
-	// UpdateLook updates a pointling's appearance
-	UpdatePointlingLook(id int64, look models.JSONMap) error
+	// UpdateLook validates and stores a pointling's appearance
+	UpdatePointlingLook(ctx context.Context, id int64, look models.Look) error
 
 	// UpdateXP updates a pointling's XP and required XP values
-	UpdatePointlingXP(id int64, currentXP, requiredXP int) error
+	UpdatePointlingXP(ctx context.Context, id int64, currentXP, requiredXP int) error
 
 	// UpdateLevel updates a pointling's level
-	UpdatePointlingLevel(id int64, level int) error
+	UpdatePointlingLevel(ctx context.Context, id int64, level int) error
 
 	// UpdateNickname sets a pointling's nickname
-	UpdatePointlingNickname(id int64, nickname *string) error
+	UpdatePointlingNickname(ctx context.Context, id int64, nickname *string) error
+
+	// AddXP records a new XP event within the daily cap, trimming its amount to
+	// what is left of the cap; the caller applies event.XPAmount to the
+	// pointling with UpdatePointlingXP
+	AddXP(ctx context.Context, event *models.XPEvent, limit models.XPDailyCap) error
+
+	// GetEventsByPointling retrieves a page of XP events for a pointling, newest first
+	GetEventsByPointling(ctx context.Context, filter models.XPHistoryFilter) ([]*models.XPEvent, error)
+
+	// GetDailyXPTotals sums XP events per day and source, newest day first
+	GetDailyXPTotals(ctx context.Context, filter models.XPHistoryFilter) ([]*models.XPDailyTotal, error)
+
+	// GetDailyXPBySource gets total XP gained from a source within a day window
+	GetDailyXPBySource(ctx context.Context, pointlingID int64, source models.XPEventSource, window models.DayWindow) (int, error)
+
+	// GetActiveXPConfig retrieves the newest XP config version, or nil if none is stored
+	GetActiveXPConfig(ctx context.Context) (*models.XPConfig, error)
+
+	// ListXPConfigVersions retrieves stored XP config versions, newest first
+	ListXPConfigVersions(ctx context.Context, limit int) ([]*models.XPConfig, error)
 
-	// AddXP creates a new XP event and updates the pointling's XP
-	AddXP(event *models.XPEvent) error
+	// CreateXPConfigVersion stores a new XP config version
+	CreateXPConfigVersion(ctx context.Context, cfg *models.XPConfig) error
 
-	// GetEventsByPointling retrieves recent XP events for a pointling
-	GetEventsByPointling(pointlingID int64, limit int) ([]*models.XPEvent, error)
+	// ListPersonalities retrieves the personality catalog
+	ListPersonalities(ctx context.Context) ([]*models.Personality, error)
 
-	// GetDailyXPBySource gets total XP gained from a source today
-	GetDailyXPBySource(pointlingID int64, source models.XPEventSource) (int, error)
+	// GetPersonality retrieves a personality by its ID
+	GetPersonality(ctx context.Context, id int) (*models.Personality, error)
 
 	// GetUser retrieves a user by ID
-	GetUser(userID int64) (*models.User, error)
+	GetUser(ctx context.Context, userID int64) (*models.User, error)
 
 	// CreateUser creates a new user
-	CreateUser(user *models.User) error
+	CreateUser(ctx context.Context, user *models.User) error
 
-	// UpdatePointBalance updates a user's point balance
-	UpdatePointBalance(userID int64, newBalance int64) error
+	// UpdateUserTimezone sets the IANA timezone used for a user's daily windows
+	UpdateUserTimezone(ctx context.Context, userID int64, timezone string) error
 
 	// ListUsers retrieves all users with optional limit/offset pagination
-	ListUsers(limit, offset int) ([]*models.User, error)
+	ListUsers(ctx context.Context, limit, offset int) ([]*models.User, error)
 
 	// Create records a new point spend transaction
-	CreatePointSpend(spend *models.PointSpend) error
+	CreatePointSpend(ctx context.Context, spend *models.PointSpend) error
 
-	// GetTotalSpentByUser gets total points spent by a user
-	GetTotalSpentByUser(userID int64) (int64, error)
+	// GetByUser lists a user's spends newest first with their items joined in
+	GetByUser(ctx context.Context, filter models.SpendHistoryFilter) ([]*models.PointSpend, error)
 
-	// SpendPoints atomically updates user balance and creates spend record
-	SpendPoints(userID int64, itemID int64, points int) error
+	// GetTotalSpentByUser gets total points spent by a user within period, excluding refunds
+	GetTotalSpentByUser(ctx context.Context, userID int64, period models.TimeRange) (int64, error)
+
+	// SpendPoints atomically creates a spend record and debits it from the ledger
+	SpendPoints(ctx context.Context, spend *models.PointSpend) error
+
+	// PostLedgerEntry applies entry.Delta to the user's balance and records it,
+	// refusing to take the balance below zero
+	PostLedgerEntry(ctx context.Context, entry *models.LedgerEntry) error
+
+	// GetLedgerEntries lists a user's ledger entries newest first, before an entry ID when > 0
+	GetLedgerEntries(ctx context.Context, userID int64, beforeID int64, limit int) ([]*models.LedgerEntry, error)
+
+	// GetLedgerBalance sums a user's ledger entries
+	GetLedgerBalance(ctx context.Context, userID int64) (int64, error)
+
+	// BackfillOpeningBalances records opening entries for users with no ledger history
+	BackfillOpeningBalances(ctx context.Context) (int64, error)
 
 	// GetByID retrieves an item by its ID
-	GetItemByID(id int64) (*models.Item, error)
+	GetItemByID(ctx context.Context, id int64) (*models.Item, error)
 
 	// List retrieves items with optional filters
-	ListItems(category *models.ItemCategory, rarity *models.ItemRarity, slot *models.ItemSlot) ([]*models.Item, error)
+	ListItems(ctx context.Context, category *models.ItemCategory, rarity *models.ItemRarity, slot *models.ItemSlot) ([]*models.Item, error)
 
 	// GetUnlocksForLevel gets items available at a specific level
-	GetUnlocksForLevel(level int) ([]*models.Item, error)
+	GetUnlocksForLevel(ctx context.Context, level int) ([]*models.Item, error)
 
 	// Create creates a new item (admin only)
-	CreateItem(item *models.Item) error
+	CreateItem(ctx context.Context, item *models.Item) error
 
 	// AddItem gives an item to a pointling
-	AddItem(pointlingID, itemID int64) error
+	AddItem(ctx context.Context, pointlingID, itemID int64) error
 
 	// GetItems lists items owned by a pointling
-	GetItems(pointlingID int64, equipped *bool) ([]*models.PointlingItem, error)
+	GetItems(ctx context.Context, pointlingID int64, equipped *bool) ([]*models.PointlingItem, error)
 
 	// ToggleEquipped equips or unequips an item
-	ToggleEquipped(pointlingID, itemID int64, equipped bool) error
+	ToggleEquipped(ctx context.Context, pointlingID, itemID int64, equipped bool) error
 
 	// GetEquippedInSlot gets the currently equipped item in a slot
-	GetEquippedInSlot(pointlingID int64, slot models.ItemSlot) (*models.PointlingItem, error)
+	GetEquippedInSlot(ctx context.Context, pointlingID int64, slot models.ItemSlot) (*models.PointlingItem, error)
+
+	// CreateLevelReward stores a new level-up offer
+	CreateLevelReward(ctx context.Context, reward *models.LevelReward) error
+
+	// GetLevelReward retrieves a level-up offer by its ID
+	GetLevelReward(ctx context.Context, rewardID int64) (*models.LevelReward, error)
+
+	// GetPendingRewards lists unclaimed, unexpired offers for a pointling
+	GetPendingRewards(ctx context.Context, pointlingID int64) ([]*models.LevelReward, error)
+
+	// ClaimLevelReward records the chosen option on an open offer
+	ClaimLevelReward(ctx context.Context, rewardID int64, option models.RewardOption) error
+
+	// AddPointlingColor gives a color to a pointling
+	AddPointlingColor(ctx context.Context, pointlingID int64, colorHex string) error
+
+	// GetPointlingColors lists colors owned by a pointling
+	GetPointlingColors(ctx context.Context, pointlingID int64) ([]*models.PointlingColor, error)
+
+	// LockPointSpend retrieves a spend and holds its row lock until the transaction ends
+	LockPointSpend(ctx context.Context, spendID int64) (*models.PointSpend, error)
+
+	// MarkSpendReversed flags a spend as refunded
+	MarkSpendReversed(ctx context.Context, spendID int64) error
+
+	// RemoveItem takes an item away from a pointling, reporting whether it was owned
+	RemoveItem(ctx context.Context, pointlingID, itemID int64) (bool, error)
+
+	// CreateSpendReversal records the audit entry for a refund
+	CreateSpendReversal(ctx context.Context, reversal *models.SpendReversal) error
+
+	// GetSpendReversal retrieves the audit entry for a refunded spend
+	GetSpendReversal(ctx context.Context, spendID int64) (*models.SpendReversal, error)
+
+	// ReserveIdempotencyKey claims a key for a request, or returns the record
+	// already holding it with reserved=false
+	ReserveIdempotencyKey(ctx context.Context, key, requestHash string, ttl time.Duration) (*models.IdempotencyRecord, bool, error)
+
+	// GetIdempotencyKey retrieves a key's record
+	GetIdempotencyKey(ctx context.Context, key string) (*models.IdempotencyRecord, error)
+
+	// SaveIdempotentResponse stores the response to replay for a reserved key
+	SaveIdempotentResponse(ctx context.Context, key string, statusCode int, contentType string, body []byte) error
+
+	// ReleaseIdempotencyKey forgets a key so the request can be retried
+	ReleaseIdempotencyKey(ctx context.Context, key string) error
+
+	// PurgeExpiredIdempotencyKeys deletes keys past their TTL
+	PurgeExpiredIdempotencyKeys(ctx context.Context) (int64, error)
+
+	// CreateAuditEntry records an admin change
+	CreateAuditEntry(ctx context.Context, entry *models.AuditEntry) error
+
+	// ListAuditEntries lists admin changes newest first
+	ListAuditEntries(ctx context.Context, filter models.AuditLogFilter) ([]*models.AuditEntry, error)
 }
 
 func New(db *sql.DB) *PointlingRepository {
@@ -101,9 +198,9 @@ func New(db *sql.DB) *PointlingRepository {
 }
 
 func (r *PointlingRepository) txWrapper() interface {
-	QueryRow(query string, args ...interface{}) *sql.Row
-	Exec(query string, args ...interface{}) (sql.Result, error)
-	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
 } {
 	if r.tx != nil {
 		return r.tx
@@ -111,8 +208,13 @@ func (r *PointlingRepository) txWrapper() interface {
 	return r.db
 }
 
-func (r *PointlingRepository) InTransaction(fn func(*PointlingRepository) error) error {
-	tx, err := r.db.BeginTx(context.Background(), nil)
+func (r *PointlingRepository) InTransaction(ctx context.Context, fn func(API) error) error {
+	// Nested calls join the surrounding transaction, so they commit or roll
+	// back with it.
+	if r.tx != nil {
+		return fn(r)
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
 	}
@@ -132,7 +234,7 @@ func (r *PointlingRepository) InTransaction(fn func(*PointlingRepository) error)
 	return nil
 }
 
-func (r *PointlingRepository) CreatePointling(pointling *models.Pointling) error {
+func (r *PointlingRepository) CreatePointling(ctx context.Context, pointling *models.Pointling) error {
 	query := `
 		INSERT INTO public.pointlings (
 			user_id, nickname, level, current_xp, required_xp,
@@ -140,12 +242,15 @@ func (r *PointlingRepository) CreatePointling(pointling *models.Pointling) error
 		) VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING pointling_id, created_at`
 
-	lookJSON, err := json.Marshal(pointling.LookJSON)
+	if err := pointling.Look.Validate(); err != nil {
+		return err
+	}
+	lookJSON, err := json.Marshal(pointling.Look)
 	if err != nil {
 		return fmt.Errorf("marshal look_json: %w", err)
 	}
 
-	err = r.db.QueryRow(
+	err = r.txWrapper().QueryRowContext(ctx,
 		query,
 		pointling.UserID,
 		pointling.Nickname,
@@ -162,17 +267,36 @@ func (r *PointlingRepository) CreatePointling(pointling *models.Pointling) error
 	return nil
 }
 
-func (r *PointlingRepository) GetPointlingByID(id int64) (*models.Pointling, error) {
+func (r *PointlingRepository) GetPointlingByID(ctx context.Context, id int64) (*models.Pointling, error) {
 	query := `
 		SELECT pointling_id, user_id, nickname, level, current_xp,
 			required_xp, personality_id, look_json, created_at
 		FROM public.pointlings
 		WHERE pointling_id = $1`
 
+	return r.queryPointling(ctx, query, id)
+}
+
+func (r *PointlingRepository) LockPointling(ctx context.Context, id int64) (*models.Pointling, error) {
+	if r.tx == nil {
+		return nil, fmt.Errorf("lock pointling %d: must be called inside InTransaction", id)
+	}
+
+	query := `
+		SELECT pointling_id, user_id, nickname, level, current_xp,
+			required_xp, personality_id, look_json, created_at
+		FROM public.pointlings
+		WHERE pointling_id = $1
+		FOR UPDATE`
+
+	return r.queryPointling(ctx, query, id)
+}
+
+func (r *PointlingRepository) queryPointling(ctx context.Context, query string, id int64) (*models.Pointling, error) {
 	pointling := &models.Pointling{}
 	var lookJSON []byte
 
-	err := r.db.QueryRow(query, id).Scan(
+	err := r.txWrapper().QueryRowContext(ctx, query, id).Scan(
 		&pointling.PointlingID,
 		&pointling.UserID,
 		&pointling.Nickname,
@@ -190,14 +314,14 @@ func (r *PointlingRepository) GetPointlingByID(id int64) (*models.Pointling, err
 		return nil, fmt.Errorf("get pointling: %w", err)
 	}
 
-	if err := json.Unmarshal(lookJSON, &pointling.LookJSON); err != nil {
+	if err := pointling.Look.Scan(lookJSON); err != nil {
 		return nil, fmt.Errorf("unmarshal look_json: %w", err)
 	}
 
 	return pointling, nil
 }
 
-func (r *PointlingRepository) GetPointlingByUserID(userID int64) ([]*models.Pointling, error) {
+func (r *PointlingRepository) GetPointlingByUserID(ctx context.Context, userID int64) ([]*models.Pointling, error) {
 	query := `
 		SELECT pointling_id, user_id, nickname, level, current_xp,
 			required_xp, personality_id, look_json, created_at
@@ -205,7 +329,7 @@ func (r *PointlingRepository) GetPointlingByUserID(userID int64) ([]*models.Poin
 		WHERE user_id = $1
 		ORDER BY created_at DESC`
 
-	rows, err := r.db.Query(query, userID)
+	rows, err := r.txWrapper().QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, fmt.Errorf("query pointlings: %w", err)
 	}
@@ -231,7 +355,7 @@ func (r *PointlingRepository) GetPointlingByUserID(userID int64) ([]*models.Poin
 			return nil, fmt.Errorf("scan pointling: %w", err)
 		}
 
-		if err := json.Unmarshal(lookJSON, &pointling.LookJSON); err != nil {
+		if err := pointling.Look.Scan(lookJSON); err != nil {
 			return nil, fmt.Errorf("unmarshal look_json: %w", err)
 		}
 
@@ -244,18 +368,21 @@ func (r *PointlingRepository) GetPointlingByUserID(userID int64) ([]*models.Poin
 	return pointlings, nil
 }
 
-func (r *PointlingRepository) UpdatePointlingLook(id int64, look models.JSONMap) error {
+func (r *PointlingRepository) UpdatePointlingLook(ctx context.Context, id int64, look models.Look) error {
 	query := `
 		UPDATE public.pointlings
 		SET look_json = $2
 		WHERE pointling_id = $1`
 
+	if err := look.Validate(); err != nil {
+		return err
+	}
 	lookJSON, err := json.Marshal(look)
 	if err != nil {
 		return fmt.Errorf("marshal look_json: %w", err)
 	}
 
-	result, err := r.db.Exec(query, id, lookJSON)
+	result, err := r.txWrapper().ExecContext(ctx, query, id, lookJSON)
 	if err != nil {
 		return fmt.Errorf("update look: %w", err)
 	}
@@ -265,19 +392,19 @@ func (r *PointlingRepository) UpdatePointlingLook(id int64, look models.JSONMap)
 		return fmt.Errorf("get rows affected: %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("pointling not found: %d", id)
+		return fmt.Errorf("%w: %d", models.ErrPointlingNotFound, id)
 	}
 
 	return nil
 }
 
-func (r *PointlingRepository) UpdatePointlingXP(id int64, currentXP, requiredXP int) error {
+func (r *PointlingRepository) UpdatePointlingXP(ctx context.Context, id int64, currentXP, requiredXP int) error {
 	query := `
 		UPDATE public.pointlings
 		SET current_xp = $2, required_xp = $3
 		WHERE pointling_id = $1`
 
-	result, err := r.db.Exec(query, id, currentXP, requiredXP)
+	result, err := r.txWrapper().ExecContext(ctx, query, id, currentXP, requiredXP)
 	if err != nil {
 		return fmt.Errorf("update xp: %w", err)
 	}
@@ -287,19 +414,19 @@ func (r *PointlingRepository) UpdatePointlingXP(id int64, currentXP, requiredXP
 		return fmt.Errorf("get rows affected: %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("pointling not found: %d", id)
+		return fmt.Errorf("%w: %d", models.ErrPointlingNotFound, id)
 	}
 
 	return nil
 }
 
-func (r *PointlingRepository) UpdatePointlingLevel(id int64, level int) error {
+func (r *PointlingRepository) UpdatePointlingLevel(ctx context.Context, id int64, level int) error {
 	query := `
 		UPDATE public.pointlings
 		SET level = $2
 		WHERE pointling_id = $1`
 
-	result, err := r.db.Exec(query, id, level)
+	result, err := r.txWrapper().ExecContext(ctx, query, id, level)
 	if err != nil {
 		return fmt.Errorf("update level: %w", err)
 	}
@@ -309,19 +436,19 @@ func (r *PointlingRepository) UpdatePointlingLevel(id int64, level int) error {
 		return fmt.Errorf("get rows affected: %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("pointling not found: %d", id)
+		return fmt.Errorf("%w: %d", models.ErrPointlingNotFound, id)
 	}
 
 	return nil
 }
 
-func (r *PointlingRepository) UpdatePointlingNickname(id int64, nickname *string) error {
+func (r *PointlingRepository) UpdatePointlingNickname(ctx context.Context, id int64, nickname *string) error {
 	query := `
 		UPDATE public.pointlings
 		SET nickname = $2
 		WHERE pointling_id = $1`
 
-	result, err := r.db.Exec(query, id, nickname)
+	result, err := r.txWrapper().ExecContext(ctx, query, id, nickname)
 	if err != nil {
 		return fmt.Errorf("update nickname: %w", err)
 	}
@@ -331,23 +458,24 @@ func (r *PointlingRepository) UpdatePointlingNickname(id int64, nickname *string
 		return fmt.Errorf("get rows affected: %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("pointling not found: %d", id)
+		return fmt.Errorf("%w: %d", models.ErrPointlingNotFound, id)
 	}
 
 	return nil
 }
 
-func (r *PointlingRepository) GetUser(userID int64) (*models.User, error) {
+func (r *PointlingRepository) GetUser(ctx context.Context, userID int64) (*models.User, error) {
 	query := `
-		SELECT user_id, display_name, point_balance, created_at
+		SELECT user_id, display_name, point_balance, timezone, created_at
 		FROM public.users
 		WHERE user_id = $1`
 
 	user := &models.User{}
-	err := r.db.QueryRow(query, userID).Scan(
+	err := r.txWrapper().QueryRowContext(ctx, query, userID).Scan(
 		&user.UserID,
 		&user.DisplayName,
 		&user.PointBalance,
+		&user.Timezone,
 		&user.CreatedAt,
 	)
 	if err == sql.ErrNoRows {
@@ -359,15 +487,20 @@ func (r *PointlingRepository) GetUser(userID int64) (*models.User, error) {
 	return user, nil
 }
 
-func (r *PointlingRepository) CreateUser(user *models.User) error {
+func (r *PointlingRepository) CreateUser(ctx context.Context, user *models.User) error {
 	query := `
-		INSERT INTO public.users (user_id, display_name, point_balance)
-		VALUES ($1, $2, $3)`
+		INSERT INTO public.users (user_id, display_name, point_balance, timezone)
+		VALUES ($1, $2, $3, $4)`
+
+	if user.Timezone == "" {
+		user.Timezone = models.DefaultTimezone
+	}
 
-	_, err := r.db.Exec(query,
+	_, err := r.txWrapper().ExecContext(ctx, query,
 		user.UserID,
 		user.DisplayName,
 		user.PointBalance,
+		user.Timezone,
 	)
 	if err != nil {
 		return fmt.Errorf("create user: %w", err)
@@ -375,15 +508,15 @@ func (r *PointlingRepository) CreateUser(user *models.User) error {
 	return nil
 }
 
-func (r *PointlingRepository) UpdatePointBalance(userID int64, newBalance int64) error {
+func (r *PointlingRepository) UpdateUserTimezone(ctx context.Context, userID int64, timezone string) error {
 	query := `
-		UPDATE public.users 
-		SET point_balance = $2
+		UPDATE public.users
+		SET timezone = $2
 		WHERE user_id = $1`
 
-	result, err := r.db.Exec(query, userID, newBalance)
+	result, err := r.txWrapper().ExecContext(ctx, query, userID, timezone)
 	if err != nil {
-		return fmt.Errorf("update point balance: %w", err)
+		return fmt.Errorf("update timezone: %w", err)
 	}
 
 	rows, err := result.RowsAffected()
@@ -391,19 +524,19 @@ func (r *PointlingRepository) UpdatePointBalance(userID int64, newBalance int64)
 		return fmt.Errorf("get rows affected: %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("user not found: %d", userID)
+		return fmt.Errorf("%w: %d", models.ErrUserNotFound, userID)
 	}
 	return nil
 }
 
-func (r *PointlingRepository) ListUsers(limit, offset int) ([]*models.User, error) {
+func (r *PointlingRepository) ListUsers(ctx context.Context, limit, offset int) ([]*models.User, error) {
 	query := `
-		SELECT user_id, display_name, point_balance, created_at
+		SELECT user_id, display_name, point_balance, timezone, created_at
 		FROM public.users
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2`
 
-	rows, err := r.db.Query(query, limit, offset)
+	rows, err := r.txWrapper().QueryContext(ctx, query, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("list users query: %w", err)
 	}
@@ -416,6 +549,7 @@ func (r *PointlingRepository) ListUsers(limit, offset int) ([]*models.User, erro
 			&user.UserID,
 			&user.DisplayName,
 			&user.PointBalance,
+			&user.Timezone,
 			&user.CreatedAt,
 		)
 		if err != nil {
@@ -429,16 +563,41 @@ func (r *PointlingRepository) ListUsers(limit, offset int) ([]*models.User, erro
 	return users, nil
 }
 
-func (r *PointlingRepository) AddXP(event *models.XPEvent) error {
-	// First check if adding this XP would exceed daily limits
-	currentDaily, err := r.GetDailyXPBySource(event.PointlingID, event.Source)
+func (r *PointlingRepository) AddXP(ctx context.Context, event *models.XPEvent, limit models.XPDailyCap) error {
+	// The cap check below is only safe while holding the pointling's row lock,
+	// so run in a transaction of our own when the caller has none.
+	if r.tx == nil {
+		return r.InTransaction(ctx, func(tx API) error {
+			return tx.AddXP(ctx, event, limit)
+		})
+	}
+
+	// Serialize XP application per pointling: concurrent callers queue here
+	// until the holder commits, then see its events in the daily total.
+	var lockedID int64
+	err := r.txWrapper().QueryRowContext(ctx,
+		`SELECT pointling_id FROM public.pointlings WHERE pointling_id = $1 FOR UPDATE`,
+		event.PointlingID,
+	).Scan(&lockedID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: %d", models.ErrPointlingNotFound, event.PointlingID)
+	}
+	if err != nil {
+		return fmt.Errorf("lock pointling: %w", err)
+	}
+
+	// First check how much of the daily cap is left
+	currentDaily, err := r.GetDailyXPBySource(ctx, event.PointlingID, event.Source, limit.Window)
 	if err != nil {
 		return fmt.Errorf("check daily xp: %w", err)
 	}
 
-	if currentDaily+event.XPAmount > event.Source.GetMaxDailyXP() {
+	// A gain that would cross the cap is granted up to it.
+	remaining := limit.Max - currentDaily
+	if remaining <= 0 {
 		return models.ErrDailyXPLimitReached
 	}
+	event.XPAmount = min(event.XPAmount, remaining)
 
 	// Insert XP event
 	query := `
@@ -447,7 +606,7 @@ func (r *PointlingRepository) AddXP(event *models.XPEvent) error {
 		) VALUES ($1, $2, $3)
 		RETURNING event_id, event_ts`
 
-	err = r.txWrapper().QueryRow(
+	err = r.txWrapper().QueryRowContext(ctx,
 		query,
 		event.PointlingID,
 		event.Source,
@@ -457,32 +616,26 @@ func (r *PointlingRepository) AddXP(event *models.XPEvent) error {
 	if err != nil {
 		return fmt.Errorf("insert xp event: %w", err)
 	}
-
-	// Update pointling's XP
-	updateQuery := `
-		UPDATE public.pointlings
-		SET current_xp = current_xp + $2
-		WHERE pointling_id = $1
-		RETURNING current_xp`
-
-	var newCurrentXP int
-	err = r.txWrapper().QueryRow(updateQuery, event.PointlingID, event.XPAmount).Scan(&newCurrentXP)
-	if err != nil {
-		return fmt.Errorf("update pointling xp: %w", err)
-	}
-
 	return nil
 }
 
-func (r *PointlingRepository) GetEventsByPointling(pointlingID int64, limit int) ([]*models.XPEvent, error) {
+func (r *PointlingRepository) GetEventsByPointling(ctx context.Context, filter models.XPHistoryFilter) ([]*models.XPEvent, error) {
 	query := `
 		SELECT event_id, pointling_id, source, xp_amount, event_ts
 		FROM public.xp_events
-		WHERE pointling_id = $1
-		ORDER BY event_ts DESC
-		LIMIT $2`
+		WHERE pointling_id = $1`
+	args := []interface{}{filter.PointlingID}
+	query, args = appendXPHistoryFilters(query, args, filter)
+
+	if filter.Cursor != nil {
+		query += fmt.Sprintf(" AND (event_ts, event_id) < ($%d, $%d)", len(args)+1, len(args)+2)
+		args = append(args, filter.Cursor.TS, filter.Cursor.ID)
+	}
 
-	rows, err := r.txWrapper().Query(query, pointlingID, limit)
+	query += fmt.Sprintf(" ORDER BY event_ts DESC, event_id DESC LIMIT $%d", len(args)+1)
+	args = append(args, filter.Limit)
+
+	rows, err := r.txWrapper().QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query xp events: %w", err)
 	}
@@ -510,17 +663,74 @@ func (r *PointlingRepository) GetEventsByPointling(pointlingID int64, limit int)
 	return events, nil
 }
 
-func (r *PointlingRepository) GetDailyXPBySource(pointlingID int64, source models.XPEventSource) (int, error) {
+func (r *PointlingRepository) GetDailyXPTotals(ctx context.Context, filter models.XPHistoryFilter) ([]*models.XPDailyTotal, error) {
+	timeZone := filter.TimeZone
+	if timeZone == "" {
+		timeZone = "UTC"
+	}
+
+	query := `
+		SELECT to_char((event_ts AT TIME ZONE $2)::date, 'YYYY-MM-DD') AS day,
+			source, SUM(xp_amount), COUNT(*)
+		FROM public.xp_events
+		WHERE pointling_id = $1`
+	args := []interface{}{filter.PointlingID, timeZone}
+	query, args = appendXPHistoryFilters(query, args, filter)
+	query += " GROUP BY day, source ORDER BY day DESC, source ASC"
+
+	rows, err := r.txWrapper().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query daily xp totals: %w", err)
+	}
+	defer rows.Close()
+
+	var totals []*models.XPDailyTotal
+	for rows.Next() {
+		total := &models.XPDailyTotal{}
+		if err := rows.Scan(&total.Day, &total.Source, &total.TotalXP, &total.EventCount); err != nil {
+			return nil, fmt.Errorf("scan daily xp total: %w", err)
+		}
+		totals = append(totals, total)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate daily xp totals: %w", err)
+	}
+
+	return totals, nil
+}
+
+func appendXPHistoryFilters(query string, args []interface{}, filter models.XPHistoryFilter) (string, []interface{}) {
+	if filter.Source != nil {
+		query += " AND source = $" + fmt.Sprint(len(args)+1)
+		args = append(args, *filter.Source)
+	}
+	return appendTimeRange(query, args, "event_ts", filter.TimeRange)
+}
+
+// appendTimeRange adds the bounds of period on column to a WHERE clause.
+func appendTimeRange(query string, args []interface{}, column string, period models.TimeRange) (string, []interface{}) {
+	if period.From != nil {
+		query += fmt.Sprintf(" AND %s >= $%d", column, len(args)+1)
+		args = append(args, *period.From)
+	}
+	if period.To != nil {
+		query += fmt.Sprintf(" AND %s < $%d", column, len(args)+1)
+		args = append(args, *period.To)
+	}
+	return query, args
+}
+
+func (r *PointlingRepository) GetDailyXPBySource(ctx context.Context, pointlingID int64, source models.XPEventSource, window models.DayWindow) (int, error) {
 	query := `
 		SELECT COALESCE(SUM(xp_amount), 0)
 		FROM public.xp_events
 		WHERE pointling_id = $1
 		AND source = $2
-		AND event_ts >= CURRENT_DATE
-		AND event_ts < CURRENT_DATE + INTERVAL '1 day'`
+		AND event_ts >= $3
+		AND event_ts < $4`
 
 	var totalXP int
-	err := r.txWrapper().QueryRow(query, pointlingID, source).Scan(&totalXP)
+	err := r.txWrapper().QueryRowContext(ctx, query, pointlingID, source, window.Start, window.End).Scan(&totalXP)
 	if err != nil {
 		return 0, fmt.Errorf("get daily xp: %w", err)
 	}
@@ -528,13 +738,13 @@ func (r *PointlingRepository) GetDailyXPBySource(pointlingID int64, source model
 	return totalXP, nil
 }
 
-func (r *PointlingRepository) AddItem(pointlingID, itemID int64) error {
+func (r *PointlingRepository) AddItem(ctx context.Context, pointlingID, itemID int64) error {
 	query := `
 		INSERT INTO public.pointling_items (pointling_id, item_id)
 		VALUES ($1, $2)
 		ON CONFLICT (pointling_id, item_id) DO NOTHING`
 
-	result, err := r.txWrapper().Exec(query, pointlingID, itemID)
+	result, err := r.txWrapper().ExecContext(ctx, query, pointlingID, itemID)
 	if err != nil {
 		return fmt.Errorf("add item: %w", err)
 	}
@@ -550,7 +760,7 @@ func (r *PointlingRepository) AddItem(pointlingID, itemID int64) error {
 	return nil
 }
 
-func (r *PointlingRepository) GetItems(pointlingID int64, equipped *bool) ([]*models.PointlingItem, error) {
+func (r *PointlingRepository) GetItems(ctx context.Context, pointlingID int64, equipped *bool) ([]*models.PointlingItem, error) {
 	query := `
 		SELECT pi.pointling_id, pi.item_id, pi.acquired_at, pi.equipped,
 			   i.category, i.slot, i.asset_id, i.name, i.rarity, i.price_points, i.unlock_level
@@ -560,7 +770,7 @@ func (r *PointlingRepository) GetItems(pointlingID int64, equipped *bool) ([]*mo
 		AND ($2::boolean IS NULL OR pi.equipped = $2)
 		ORDER BY pi.acquired_at DESC`
 
-	rows, err := r.txWrapper().Query(query, pointlingID, equipped)
+	rows, err := r.txWrapper().QueryContext(ctx, query, pointlingID, equipped)
 	if err != nil {
 		return nil, fmt.Errorf("get items query: %w", err)
 	}
@@ -596,7 +806,7 @@ func (r *PointlingRepository) GetItems(pointlingID int64, equipped *bool) ([]*mo
 	return items, nil
 }
 
-func (r *PointlingRepository) GetEquippedInSlot(pointlingID int64, slot models.ItemSlot) (*models.PointlingItem, error) {
+func (r *PointlingRepository) GetEquippedInSlot(ctx context.Context, pointlingID int64, slot models.ItemSlot) (*models.PointlingItem, error) {
 	query := `
 		SELECT pi.pointling_id, pi.item_id, pi.acquired_at, pi.equipped,
 			   i.category, i.slot, i.asset_id, i.name, i.rarity, i.price_points, i.unlock_level
@@ -609,7 +819,7 @@ func (r *PointlingRepository) GetEquippedInSlot(pointlingID int64, slot models.I
 	pi := &models.PointlingItem{
 		Item: &models.Item{},
 	}
-	err := r.txWrapper().QueryRow(query, pointlingID, slot).Scan(
+	err := r.txWrapper().QueryRowContext(ctx, query, pointlingID, slot).Scan(
 		&pi.PointlingID,
 		&pi.ItemID,
 		&pi.AcquiredAt,
@@ -632,7 +842,7 @@ func (r *PointlingRepository) GetEquippedInSlot(pointlingID int64, slot models.I
 	return pi, nil
 }
 
-func (r *PointlingRepository) ToggleEquipped(pointlingID, itemID int64, equipped bool) error {
+func (r *PointlingRepository) ToggleEquipped(ctx context.Context, pointlingID, itemID int64, equipped bool) error {
 	if equipped {
 		// If equipping, first unequip any item in the same slot
 		unequipQuery := `
@@ -648,7 +858,7 @@ func (r *PointlingRepository) ToggleEquipped(pointlingID, itemID int64, equipped
 			)
 			AND pi1.equipped = true`
 
-		if _, err := r.txWrapper().Exec(unequipQuery, pointlingID, itemID); err != nil {
+		if _, err := r.txWrapper().ExecContext(ctx, unequipQuery, pointlingID, itemID); err != nil {
 			return fmt.Errorf("unequip current item: %w", err)
 		}
 	}
@@ -660,7 +870,7 @@ func (r *PointlingRepository) ToggleEquipped(pointlingID, itemID int64, equipped
 		WHERE pointling_id = $1
 		AND item_id = $2`
 
-	result, err := r.txWrapper().Exec(query, pointlingID, itemID, equipped)
+	result, err := r.txWrapper().ExecContext(ctx, query, pointlingID, itemID, equipped)
 	if err != nil {
 		return fmt.Errorf("toggle equipped: %w", err)
 	}
@@ -670,22 +880,23 @@ func (r *PointlingRepository) ToggleEquipped(pointlingID, itemID int64, equipped
 		return fmt.Errorf("get rows affected: %w", err)
 	}
 	if rows == 0 {
-		return fmt.Errorf("pointling does not own this item")
+		return models.ErrItemNotOwned
 	}
 
 	return nil
 }
 
-func (r *PointlingRepository) CreatePointSpend(spend *models.PointSpend) error {
+func (r *PointlingRepository) CreatePointSpend(ctx context.Context, spend *models.PointSpend) error {
 	query := `
 		INSERT INTO public.point_spend (
-			user_id, item_id, points_spent
-		) VALUES ($1, $2, $3)
+			user_id, pointling_id, item_id, points_spent
+		) VALUES ($1, $2, $3, $4)
 		RETURNING spend_id, spend_ts`
 
-	err := r.txWrapper().QueryRow(
+	err := r.txWrapper().QueryRowContext(ctx,
 		query,
 		spend.UserID,
+		spend.PointlingID,
 		spend.ItemID,
 		spend.PointsSpent,
 	).Scan(&spend.SpendID, &spend.SpendTS)
@@ -696,17 +907,26 @@ func (r *PointlingRepository) CreatePointSpend(spend *models.PointSpend) error {
 	return nil
 }
 
-func (r *PointlingRepository) GetByUser(userID int64, limit, offset int) ([]*models.PointSpend, error) {
+func (r *PointlingRepository) GetByUser(ctx context.Context, filter models.SpendHistoryFilter) ([]*models.PointSpend, error) {
 	query := `
-		SELECT ps.spend_id, ps.user_id, ps.item_id, ps.points_spent, ps.spend_ts,
+		SELECT ps.spend_id, ps.user_id, ps.pointling_id, ps.item_id, ps.points_spent,
+			   ps.spend_ts, ps.reversed_at,
 			   i.category, i.slot, i.asset_id, i.name, i.rarity, i.price_points, i.unlock_level
 		FROM public.point_spend ps
 		JOIN public.items i ON i.item_id = ps.item_id
-		WHERE ps.user_id = $1
-		ORDER BY ps.spend_ts DESC
-		LIMIT $2 OFFSET $3`
+		WHERE ps.user_id = $1`
+	args := []interface{}{filter.UserID}
+
+	query, args = appendTimeRange(query, args, "ps.spend_ts", filter.TimeRange)
+	if filter.Cursor != nil {
+		query += fmt.Sprintf(" AND (ps.spend_ts, ps.spend_id) < ($%d, $%d)", len(args)+1, len(args)+2)
+		args = append(args, filter.Cursor.TS, filter.Cursor.ID)
+	}
+
+	query += fmt.Sprintf(" ORDER BY ps.spend_ts DESC, ps.spend_id DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.Offset)
 
-	rows, err := r.txWrapper().Query(query, userID, limit, offset)
+	rows, err := r.txWrapper().QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("get user spends query: %w", err)
 	}
@@ -720,9 +940,11 @@ func (r *PointlingRepository) GetByUser(userID int64, limit, offset int) ([]*mod
 		err := rows.Scan(
 			&spend.SpendID,
 			&spend.UserID,
+			&spend.PointlingID,
 			&spend.ItemID,
 			&spend.PointsSpent,
 			&spend.SpendTS,
+			&spend.ReversedAt,
 			&spend.Item.Category,
 			&spend.Item.Slot,
 			&spend.Item.AssetID,
@@ -743,59 +965,51 @@ func (r *PointlingRepository) GetByUser(userID int64, limit, offset int) ([]*mod
 	return spends, nil
 }
 
-func (r *PointlingRepository) GetTotalSpentByUser(userID int64) (int64, error) {
+func (r *PointlingRepository) GetTotalSpentByUser(ctx context.Context, userID int64, period models.TimeRange) (int64, error) {
 	query := `
 		SELECT COALESCE(SUM(points_spent), 0)
 		FROM public.point_spend
-		WHERE user_id = $1`
+		WHERE user_id = $1
+		AND reversed_at IS NULL`
+	query, args := appendTimeRange(query, []interface{}{userID}, "spend_ts", period)
 
 	var total int64
-	err := r.txWrapper().QueryRow(query, userID).Scan(&total)
+	err := r.txWrapper().QueryRowContext(ctx, query, args...).Scan(&total)
 	if err != nil {
 		return 0, fmt.Errorf("get total spent: %w", err)
 	}
 	return total, nil
 }
 
-func (r *PointlingRepository) SpendPoints(userID int64, itemID int64, points int) error {
-	// First check and update user balance atomically
-	balanceQuery := `
-		UPDATE public.users
-		SET point_balance = point_balance - $2
-		WHERE user_id = $1
-		AND point_balance >= $2
-		RETURNING point_balance`
-
-	var newBalance int64
-	err := r.txWrapper().QueryRow(balanceQuery, userID, points).Scan(&newBalance)
-	if err == sql.ErrNoRows {
-		return models.ErrInsufficientBalance
-	}
-	if err != nil {
-		return fmt.Errorf("update balance: %w", err)
+func (r *PointlingRepository) SpendPoints(ctx context.Context, spend *models.PointSpend) error {
+	if r.tx == nil {
+		return r.InTransaction(ctx, func(tx API) error {
+			return tx.SpendPoints(ctx, spend)
+		})
 	}
 
-	// Create spend record
-	spend := &models.PointSpend{
-		UserID:      userID,
-		ItemID:      itemID,
-		PointsSpent: points,
-	}
-	if err := r.CreatePointSpend(spend); err != nil {
-		return fmt.Errorf("create spend record: %w", err)
+	// Create the spend record first so the debit can reference it
+	if err := r.CreatePointSpend(ctx, spend); err != nil {
+		return err
 	}
 
-	return nil
+	reference := models.SpendReference(spend.SpendID)
+	return r.PostLedgerEntry(ctx, &models.LedgerEntry{
+		UserID:    spend.UserID,
+		Delta:     -int64(spend.PointsSpent),
+		Reason:    models.LedgerReasonSpend,
+		Reference: &reference,
+	})
 }
 
-func (r *PointlingRepository) GetItemByID(id int64) (*models.Item, error) {
+func (r *PointlingRepository) GetItemByID(ctx context.Context, id int64) (*models.Item, error) {
 	query := `
 		SELECT item_id, category, slot, asset_id, name, rarity, price_points, unlock_level
 		FROM public.items
 		WHERE item_id = $1`
 
 	item := &models.Item{}
-	err := r.db.QueryRow(query, id).Scan(
+	err := r.txWrapper().QueryRowContext(ctx, query, id).Scan(
 		&item.ItemID,
 		&item.Category,
 		&item.Slot,
@@ -814,16 +1028,16 @@ func (r *PointlingRepository) GetItemByID(id int64) (*models.Item, error) {
 	return item, nil
 }
 
-func (r *PointlingRepository) List(category *models.ItemCategory, rarity *models.ItemRarity, slot *models.ItemSlot) ([]*models.Item, error) {
+func (r *PointlingRepository) List(ctx context.Context, category *models.ItemCategory, rarity *models.ItemRarity, slot *models.ItemSlot) ([]*models.Item, error) {
 	query := `
 		SELECT item_id, category, slot, asset_id, name, rarity, price_points, unlock_level
 		FROM public.items
-		WHERE ($1::text IS NULL OR category = $1)
-		AND ($2::text IS NULL OR rarity = $2)
-		AND ($3::text IS NULL OR slot = $3)
+		WHERE ($1::text IS NULL OR category::text = $1)
+		AND ($2::text IS NULL OR rarity::text = $2)
+		AND ($3::text IS NULL OR slot::text = $3)
 		ORDER BY rarity, name`
 
-	rows, err := r.db.Query(query, category, rarity, slot)
+	rows, err := r.txWrapper().QueryContext(ctx, query, category, rarity, slot)
 	if err != nil {
 		return nil, fmt.Errorf("list items query: %w", err)
 	}
@@ -853,14 +1067,14 @@ func (r *PointlingRepository) List(category *models.ItemCategory, rarity *models
 	return items, nil
 }
 
-func (r *PointlingRepository) GetUnlocksForLevel(level int) ([]*models.Item, error) {
+func (r *PointlingRepository) GetUnlocksForLevel(ctx context.Context, level int) ([]*models.Item, error) {
 	query := `
 		SELECT item_id, category, slot, asset_id, name, rarity, price_points, unlock_level
 		FROM public.items
 		WHERE unlock_level = $1
 		ORDER BY rarity, name`
 
-	rows, err := r.db.Query(query, level)
+	rows, err := r.txWrapper().QueryContext(ctx, query, level)
 	if err != nil {
 		return nil, fmt.Errorf("get level unlocks query: %w", err)
 	}
@@ -890,14 +1104,14 @@ func (r *PointlingRepository) GetUnlocksForLevel(level int) ([]*models.Item, err
 	return items, nil
 }
 
-func (r *PointlingRepository) CreateItem(item *models.Item) error {
+func (r *PointlingRepository) CreateItem(ctx context.Context, item *models.Item) error {
 	query := `
 		INSERT INTO public.items (
 			category, slot, asset_id, name, rarity, price_points, unlock_level
 		) VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING item_id`
 
-	err := r.db.QueryRow(
+	err := r.txWrapper().QueryRowContext(ctx,
 		query,
 		item.Category,
 		item.Slot,
@@ -914,7 +1128,7 @@ func (r *PointlingRepository) CreateItem(item *models.Item) error {
 	return nil
 }
 
-func (r *PointlingRepository) ListItems(category *models.ItemCategory, rarity *models.ItemRarity, slot *models.ItemSlot) ([]*models.Item, error) {
+func (r *PointlingRepository) ListItems(ctx context.Context, category *models.ItemCategory, rarity *models.ItemRarity, slot *models.ItemSlot) ([]*models.Item, error) {
 	query := "SELECT item_id, category, slot, asset_id, name, rarity, price_points, unlock_level FROM items WHERE 1=1"
 	args := []interface{}{}
 
@@ -935,7 +1149,7 @@ func (r *PointlingRepository) ListItems(category *models.ItemCategory, rarity *m
 
 	query += " ORDER BY name ASC"
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.txWrapper().QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("list items query: %w", err)
 	}
@@ -0,0 +1,86 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+
+	"my-pointlings-be/internal/database/databasetest"
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository"
+	"my-pointlings-be/internal/repository/repositorytest"
+
+	"github.com/stretchr/testify/require"
+)
+
+// appTables are emptied between cases; schema_migrations is left alone. The
+// suite truncates them in whatever database databasetest.EnvURL names.
+const appTables = `public.users, public.personalities, public.pointlings,
+	public.items, public.pointling_items, public.pointling_colors,
+	public.xp_config_versions, public.xp_events, public.level_rewards,
+	public.point_spend, public.point_ledger, public.spend_reversals,
+	public.idempotency_keys, public.admin_audit_log`
+
+var testServer *databasetest.Server
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	testServer = databasetest.Start()
+	code := m.Run()
+	testServer.Stop()
+	os.Exit(code)
+}
+
+func TestConformance(t *testing.T) {
+	db := testServer.Open(t)
+	ctx := context.Background()
+
+	repositorytest.Run(t, func(t *testing.T) repository.API {
+		_, err := db.ExecContext(ctx, "TRUNCATE "+appTables+" RESTART IDENTITY CASCADE")
+		require.NoError(t, err)
+		return &seededRepository{PointlingRepository: repository.New(db), t: t, db: db}
+	})
+}
+
+// seededRepository adds the personality loader the suite uses to Postgres.
+type seededRepository struct {
+	*repository.PointlingRepository
+	t  *testing.T
+	db *sql.DB
+}
+
+func (r *seededRepository) SeedPersonalities(personalities ...models.Personality) {
+	r.t.Helper()
+	query := `
+		INSERT INTO public.personalities
+			(personality_id, code, name, traits, idle_animation_ids, dialogue_tags, weight, xp_bonus_percent)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	for _, p := range personalities {
+		bonus := p.XPBonusPercent
+		if bonus == nil {
+			bonus = map[models.XPEventSource]int{}
+		}
+		_, err := r.db.Exec(query, p.PersonalityID, p.Code, p.Name,
+			jsonArg(r.t, nonNil(p.Traits)), jsonArg(r.t, nonNil(p.IdleAnimationIDs)),
+			jsonArg(r.t, nonNil(p.DialogueTags)), p.Weight, jsonArg(r.t, bonus))
+		require.NoError(r.t, err)
+	}
+}
+
+func jsonArg(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
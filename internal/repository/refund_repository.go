@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"my-pointlings-be/internal/models"
+)
+
+func (r *PointlingRepository) LockPointSpend(ctx context.Context, spendID int64) (*models.PointSpend, error) {
+	if r.tx == nil {
+		return nil, fmt.Errorf("lock point spend %d: must be called inside InTransaction", spendID)
+	}
+
+	query := `
+		SELECT spend_id, user_id, pointling_id, item_id, points_spent, spend_ts, reversed_at
+		FROM public.point_spend
+		WHERE spend_id = $1
+		FOR UPDATE`
+
+	spend := &models.PointSpend{}
+	err := r.txWrapper().QueryRowContext(ctx, query, spendID).Scan(
+		&spend.SpendID,
+		&spend.UserID,
+		&spend.PointlingID,
+		&spend.ItemID,
+		&spend.PointsSpent,
+		&spend.SpendTS,
+		&spend.ReversedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lock point spend: %w", err)
+	}
+	return spend, nil
+}
+
+func (r *PointlingRepository) MarkSpendReversed(ctx context.Context, spendID int64) error {
+	query := `
+		UPDATE public.point_spend
+		SET reversed_at = now()
+		WHERE spend_id = $1
+		AND reversed_at IS NULL`
+
+	result, err := r.txWrapper().ExecContext(ctx, query, spendID)
+	if err != nil {
+		return fmt.Errorf("mark spend reversed: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("%w: %d", models.ErrSpendNotFound, spendID)
+	}
+	return nil
+}
+
+func (r *PointlingRepository) RemoveItem(ctx context.Context, pointlingID, itemID int64) (bool, error) {
+	query := `
+		DELETE FROM public.pointling_items
+		WHERE pointling_id = $1
+		AND item_id = $2`
+
+	result, err := r.txWrapper().ExecContext(ctx, query, pointlingID, itemID)
+	if err != nil {
+		return false, fmt.Errorf("remove item: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+func (r *PointlingRepository) CreateSpendReversal(ctx context.Context, reversal *models.SpendReversal) error {
+	query := `
+		INSERT INTO public.spend_reversals (
+			spend_id, user_id, pointling_id, item_id, points_refunded,
+			ledger_entry_id, item_revoked, reason, actor
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING reversed_at`
+
+	err := r.txWrapper().QueryRowContext(ctx, query,
+		reversal.SpendID,
+		reversal.UserID,
+		reversal.PointlingID,
+		reversal.ItemID,
+		reversal.PointsRefunded,
+		reversal.LedgerEntryID,
+		reversal.ItemRevoked,
+		reversal.Reason,
+		reversal.Actor,
+	).Scan(&reversal.ReversedAt)
+	if err != nil {
+		return fmt.Errorf("create spend reversal: %w", err)
+	}
+	return nil
+}
+
+func (r *PointlingRepository) GetSpendReversal(ctx context.Context, spendID int64) (*models.SpendReversal, error) {
+	query := `
+		SELECT spend_id, user_id, pointling_id, item_id, points_refunded,
+			ledger_entry_id, item_revoked, reason, actor, reversed_at
+		FROM public.spend_reversals
+		WHERE spend_id = $1`
+
+	reversal := &models.SpendReversal{}
+	err := r.txWrapper().QueryRowContext(ctx, query, spendID).Scan(
+		&reversal.SpendID,
+		&reversal.UserID,
+		&reversal.PointlingID,
+		&reversal.ItemID,
+		&reversal.PointsRefunded,
+		&reversal.LedgerEntryID,
+		&reversal.ItemRevoked,
+		&reversal.Reason,
+		&reversal.Actor,
+		&reversal.ReversedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get spend reversal: %w", err)
+	}
+	return reversal, nil
+}
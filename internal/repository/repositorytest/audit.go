@@ -0,0 +1,78 @@
+package repositorytest
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository"
+
+	"github.com/stretchr/testify/require"
+)
+
+func recordAudit(t *testing.T, repo repository.API, targetType, targetID string) *models.AuditEntry {
+	t.Helper()
+	entry := &models.AuditEntry{
+		Actor:      "api-key:ops",
+		Action:     models.AuditActionItemCreate,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     json.RawMessage(`null`),
+		After:      json.RawMessage(`{"name":"Hat"}`),
+	}
+	require.NoError(t, repo.CreateAuditEntry(context.Background(), entry))
+	return entry
+}
+
+var auditCases = []testCase{
+	{"CreateAndList", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		entry := recordAudit(t, repo, "item", "1")
+		require.NotZero(t, entry.AuditID)
+		require.False(t, entry.CreatedAt.IsZero())
+
+		entries, err := repo.ListAuditEntries(ctx, models.AuditLogFilter{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Equal(t, entry.AuditID, entries[0].AuditID)
+		require.Equal(t, "api-key:ops", entries[0].Actor)
+		require.Equal(t, models.AuditActionItemCreate, entries[0].Action)
+		require.JSONEq(t, `{"name":"Hat"}`, string(entries[0].After))
+		require.JSONEq(t, `null`, string(entries[0].Before))
+	}},
+	{"ListFilteredNewestFirst", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		first := recordAudit(t, repo, "item", "1")
+		second := recordAudit(t, repo, "user", "7")
+		third := recordAudit(t, repo, "item", "2")
+
+		entries, err := repo.ListAuditEntries(ctx, models.AuditLogFilter{Limit: 2})
+		require.NoError(t, err)
+		require.Equal(t, []int64{third.AuditID, second.AuditID}, auditIDs(entries))
+
+		entries, err = repo.ListAuditEntries(ctx, models.AuditLogFilter{Limit: 10, BeforeID: second.AuditID})
+		require.NoError(t, err)
+		require.Equal(t, []int64{first.AuditID}, auditIDs(entries))
+
+		entries, err = repo.ListAuditEntries(ctx, models.AuditLogFilter{Limit: 10, TargetType: "item"})
+		require.NoError(t, err)
+		require.Equal(t, []int64{third.AuditID, first.AuditID}, auditIDs(entries))
+
+		entries, err = repo.ListAuditEntries(ctx, models.AuditLogFilter{Limit: 10, TargetType: "item", TargetID: "1"})
+		require.NoError(t, err)
+		require.Equal(t, []int64{first.AuditID}, auditIDs(entries))
+
+		entries, err = repo.ListAuditEntries(ctx, models.AuditLogFilter{Limit: 10, TargetType: "pointling"})
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	}},
+}
+
+func auditIDs(entries []*models.AuditEntry) []int64 {
+	ids := make([]int64, len(entries))
+	for i, e := range entries {
+		ids[i] = e.AuditID
+	}
+	return ids
+}
@@ -0,0 +1,215 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository"
+
+	"github.com/stretchr/testify/require"
+)
+
+var itemCases = []testCase{
+	{"CreateAndGet", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		item := createItem(t, repo, models.Item{
+			Name:        "Top Hat",
+			AssetID:     "hat_top",
+			Slot:        ptr(models.SlotHat),
+			Rarity:      models.RarityEpic,
+			PricePoints: ptr(40),
+			UnlockLevel: ptr(3),
+		})
+		require.NotZero(t, item.ItemID)
+
+		got, err := repo.GetItemByID(ctx, item.ItemID)
+		require.NoError(t, err)
+		require.Equal(t, item, got)
+
+		feature := createItem(t, repo, models.Item{Name: "Sparkles", Category: models.CategoryFeature})
+		got, err = repo.GetItemByID(ctx, feature.ItemID)
+		require.NoError(t, err)
+		require.Nil(t, got.Slot)
+		require.Nil(t, got.PricePoints)
+		require.Nil(t, got.UnlockLevel)
+	}},
+	{"GetMissing", func(t *testing.T, repo repository.API) {
+		got, err := repo.GetItemByID(context.Background(), 404)
+		require.NoError(t, err)
+		require.Nil(t, got)
+	}},
+	{"ListFilteredByName", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		beret := createItem(t, repo, models.Item{Name: "Beret", Rarity: models.RarityRare})
+		boots := createItem(t, repo, models.Item{Name: "Boots", Slot: ptr(models.SlotShoes)})
+		glow := createItem(t, repo, models.Item{Name: "Glow", Category: models.CategoryFeature, Rarity: models.RarityRare})
+
+		items, err := repo.ListItems(ctx, nil, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, []int64{beret.ItemID, boots.ItemID, glow.ItemID}, itemIDs(items))
+
+		items, err = repo.ListItems(ctx, ptr(models.CategoryAccessory), nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, []int64{beret.ItemID, boots.ItemID}, itemIDs(items))
+
+		items, err = repo.ListItems(ctx, nil, ptr(models.RarityRare), nil)
+		require.NoError(t, err)
+		require.Equal(t, []int64{beret.ItemID, glow.ItemID}, itemIDs(items))
+
+		items, err = repo.ListItems(ctx, nil, nil, ptr(models.SlotShoes))
+		require.NoError(t, err)
+		require.Equal(t, []int64{boots.ItemID}, itemIDs(items))
+
+		items, err = repo.ListItems(ctx, nil, nil, ptr(models.SlotWings))
+		require.NoError(t, err)
+		require.Empty(t, items)
+	}},
+	{"UnlocksForLevelByRarityThenName", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		legendary := createItem(t, repo, models.Item{Name: "Crown", Rarity: models.RarityLegendary, UnlockLevel: ptr(5)})
+		commonB := createItem(t, repo, models.Item{Name: "Beanie", UnlockLevel: ptr(5)})
+		commonA := createItem(t, repo, models.Item{Name: "Antenna", UnlockLevel: ptr(5)})
+		rare := createItem(t, repo, models.Item{Name: "Visor", Rarity: models.RarityRare, UnlockLevel: ptr(5)})
+		createItem(t, repo, models.Item{Name: "Later", UnlockLevel: ptr(6)})
+		createItem(t, repo, models.Item{Name: "Always"})
+
+		items, err := repo.GetUnlocksForLevel(ctx, 5)
+		require.NoError(t, err)
+		require.Equal(t, []int64{commonA.ItemID, commonB.ItemID, rare.ItemID, legendary.ItemID}, itemIDs(items))
+
+		items, err = repo.GetUnlocksForLevel(ctx, 2)
+		require.NoError(t, err)
+		require.Empty(t, items)
+	}},
+}
+
+var inventoryCases = []testCase{
+	{"AddAndList", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+		id := createPointling(t, repo, 1).PointlingID
+		hat := createItem(t, repo, models.Item{Name: "Hat"})
+		shoes := createItem(t, repo, models.Item{Name: "Shoes", Slot: ptr(models.SlotShoes)})
+
+		require.NoError(t, repo.AddItem(ctx, id, hat.ItemID))
+		require.NoError(t, repo.AddItem(ctx, id, shoes.ItemID))
+
+		owned, err := repo.GetItems(ctx, id, nil)
+		require.NoError(t, err)
+		require.Equal(t, []int64{shoes.ItemID, hat.ItemID}, ownedIDs(owned))
+		require.Equal(t, shoes, owned[0].Item)
+		require.False(t, owned[0].Equipped)
+		require.False(t, owned[0].AcquiredAt.IsZero())
+
+		owned, err = repo.GetItems(ctx, 404, nil)
+		require.NoError(t, err)
+		require.Empty(t, owned)
+	}},
+	{"AddDuplicate", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+		id := createPointling(t, repo, 1).PointlingID
+		hat := createItem(t, repo, models.Item{Name: "Hat"})
+
+		require.NoError(t, repo.AddItem(ctx, id, hat.ItemID))
+		require.ErrorIs(t, repo.AddItem(ctx, id, hat.ItemID), models.ErrAlreadyOwned)
+	}},
+	{"AddMissingItem", func(t *testing.T, repo repository.API) {
+		createUser(t, repo, 1, 0)
+		id := createPointling(t, repo, 1).PointlingID
+		require.Error(t, repo.AddItem(context.Background(), id, 404))
+	}},
+	{"EquipOnePerSlot", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+		id := createPointling(t, repo, 1).PointlingID
+		other := createPointling(t, repo, 1).PointlingID
+		first := createItem(t, repo, models.Item{Name: "First Hat"})
+		second := createItem(t, repo, models.Item{Name: "Second Hat"})
+		shoes := createItem(t, repo, models.Item{Name: "Shoes", Slot: ptr(models.SlotShoes)})
+		for _, item := range []*models.Item{first, second, shoes} {
+			require.NoError(t, repo.AddItem(ctx, id, item.ItemID))
+		}
+		require.NoError(t, repo.AddItem(ctx, other, first.ItemID))
+		require.NoError(t, repo.ToggleEquipped(ctx, other, first.ItemID, true))
+
+		require.NoError(t, repo.ToggleEquipped(ctx, id, first.ItemID, true))
+		require.NoError(t, repo.ToggleEquipped(ctx, id, shoes.ItemID, true))
+		require.NoError(t, repo.ToggleEquipped(ctx, id, second.ItemID, true))
+
+		equipped, err := repo.GetItems(ctx, id, ptr(true))
+		require.NoError(t, err)
+		require.ElementsMatch(t, []int64{second.ItemID, shoes.ItemID}, ownedIDs(equipped))
+
+		unequipped, err := repo.GetItems(ctx, id, ptr(false))
+		require.NoError(t, err)
+		require.Equal(t, []int64{first.ItemID}, ownedIDs(unequipped))
+
+		inHat, err := repo.GetEquippedInSlot(ctx, id, models.SlotHat)
+		require.NoError(t, err)
+		require.Equal(t, second.ItemID, inHat.ItemID)
+		require.Equal(t, second, inHat.Item)
+
+		// Another pointling's slot is untouched.
+		otherHat, err := repo.GetEquippedInSlot(ctx, other, models.SlotHat)
+		require.NoError(t, err)
+		require.Equal(t, first.ItemID, otherHat.ItemID)
+
+		require.NoError(t, repo.ToggleEquipped(ctx, id, second.ItemID, false))
+		inHat, err = repo.GetEquippedInSlot(ctx, id, models.SlotHat)
+		require.NoError(t, err)
+		require.Nil(t, inHat)
+	}},
+	{"EquipNotOwned", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+		id := createPointling(t, repo, 1).PointlingID
+		hat := createItem(t, repo, models.Item{Name: "Hat"})
+
+		require.ErrorIs(t, repo.ToggleEquipped(ctx, id, hat.ItemID, true), models.ErrItemNotOwned)
+		require.ErrorIs(t, repo.ToggleEquipped(ctx, id, hat.ItemID, false), models.ErrItemNotOwned)
+	}},
+	{"EmptySlot", func(t *testing.T, repo repository.API) {
+		createUser(t, repo, 1, 0)
+		id := createPointling(t, repo, 1).PointlingID
+		got, err := repo.GetEquippedInSlot(context.Background(), id, models.SlotWings)
+		require.NoError(t, err)
+		require.Nil(t, got)
+	}},
+	{"Remove", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+		id := createPointling(t, repo, 1).PointlingID
+		hat := createItem(t, repo, models.Item{Name: "Hat"})
+		require.NoError(t, repo.AddItem(ctx, id, hat.ItemID))
+
+		removed, err := repo.RemoveItem(ctx, id, hat.ItemID)
+		require.NoError(t, err)
+		require.True(t, removed)
+
+		removed, err = repo.RemoveItem(ctx, id, hat.ItemID)
+		require.NoError(t, err)
+		require.False(t, removed)
+
+		owned, err := repo.GetItems(ctx, id, nil)
+		require.NoError(t, err)
+		require.Empty(t, owned)
+	}},
+}
+
+func itemIDs(items []*models.Item) []int64 {
+	ids := make([]int64, len(items))
+	for i, item := range items {
+		ids[i] = item.ItemID
+	}
+	return ids
+}
+
+func ownedIDs(owned []*models.PointlingItem) []int64 {
+	ids := make([]int64, len(owned))
+	for i, pi := range owned {
+		ids[i] = pi.ItemID
+	}
+	return ids
+}
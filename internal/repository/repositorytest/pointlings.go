@@ -0,0 +1,154 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository"
+
+	"github.com/stretchr/testify/require"
+)
+
+var pointlingCases = []testCase{
+	{"CreateAndGet", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+		pointling := models.NewPointling(1, ptr("Pip"))
+		pointling.Look.Slots[models.SlotHat] = "hat_1"
+		require.NoError(t, repo.CreatePointling(ctx, pointling))
+		require.NotZero(t, pointling.PointlingID)
+		require.False(t, pointling.CreatedAt.IsZero())
+
+		got, err := repo.GetPointlingByID(ctx, pointling.PointlingID)
+		require.NoError(t, err)
+		require.Equal(t, pointling.PointlingID, got.PointlingID)
+		require.Equal(t, int64(1), got.UserID)
+		require.Equal(t, "Pip", *got.Nickname)
+		require.Equal(t, 1, got.Level)
+		require.Equal(t, pointling.RequiredXP, got.RequiredXP)
+		require.Nil(t, got.PersonalityID)
+		require.Equal(t, pointling.Look, got.Look)
+		require.True(t, pointling.CreatedAt.Equal(got.CreatedAt))
+	}},
+	{"GetMissing", func(t *testing.T, repo repository.API) {
+		got, err := repo.GetPointlingByID(context.Background(), 404)
+		require.NoError(t, err)
+		require.Nil(t, got)
+	}},
+	{"CreateRejectsInvalidLook", func(t *testing.T, repo repository.API) {
+		createUser(t, repo, 1, 0)
+		pointling := models.NewPointling(1, nil)
+		pointling.Look.BaseBody = "Not Valid"
+		err := repo.CreatePointling(context.Background(), pointling)
+		require.ErrorIs(t, err, models.ErrInvalidLook)
+	}},
+	{"CreateForMissingUser", func(t *testing.T, repo repository.API) {
+		err := repo.CreatePointling(context.Background(), models.NewPointling(404, nil))
+		require.Error(t, err)
+	}},
+	{"StoredCopyIsIndependent", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+		pointling := createPointling(t, repo, 1)
+		pointling.Look.Slots[models.SlotHat] = "changed"
+
+		got, err := repo.GetPointlingByID(ctx, pointling.PointlingID)
+		require.NoError(t, err)
+		require.Empty(t, got.Look.Slots)
+		got.Look.Slots[models.SlotShoes] = "changed"
+
+		again, err := repo.GetPointlingByID(ctx, pointling.PointlingID)
+		require.NoError(t, err)
+		require.Empty(t, again.Look.Slots)
+	}},
+	{"ListByUserNewestFirst", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+		createUser(t, repo, 2, 0)
+		first := createPointling(t, repo, 1)
+		createPointling(t, repo, 2)
+		second := createPointling(t, repo, 1)
+
+		pointlings, err := repo.GetPointlingByUserID(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, []int64{second.PointlingID, first.PointlingID}, pointlingIDs(pointlings))
+
+		pointlings, err = repo.GetPointlingByUserID(ctx, 404)
+		require.NoError(t, err)
+		require.Empty(t, pointlings)
+	}},
+	{"Updates", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+		id := createPointling(t, repo, 1).PointlingID
+
+		look := models.NewLook()
+		look.Color = "#123ABC"
+		look.Features = []string{"sparkles"}
+		require.NoError(t, repo.UpdatePointlingLook(ctx, id, look))
+		require.NoError(t, repo.UpdatePointlingXP(ctx, id, 4, 12))
+		require.NoError(t, repo.UpdatePointlingLevel(ctx, id, 5))
+		require.NoError(t, repo.UpdatePointlingNickname(ctx, id, ptr("Nova")))
+
+		got, err := repo.GetPointlingByID(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, look, got.Look)
+		require.Equal(t, 4, got.CurrentXP)
+		require.Equal(t, 12, got.RequiredXP)
+		require.Equal(t, 5, got.Level)
+		require.Equal(t, "Nova", *got.Nickname)
+
+		require.NoError(t, repo.UpdatePointlingNickname(ctx, id, nil))
+		got, err = repo.GetPointlingByID(ctx, id)
+		require.NoError(t, err)
+		require.Nil(t, got.Nickname)
+	}},
+	{"UpdatesMissing", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		require.ErrorIs(t, repo.UpdatePointlingLook(ctx, 404, models.NewLook()), models.ErrPointlingNotFound)
+		require.ErrorIs(t, repo.UpdatePointlingXP(ctx, 404, 1, 2), models.ErrPointlingNotFound)
+		require.ErrorIs(t, repo.UpdatePointlingLevel(ctx, 404, 2), models.ErrPointlingNotFound)
+		require.ErrorIs(t, repo.UpdatePointlingNickname(ctx, 404, nil), models.ErrPointlingNotFound)
+	}},
+	{"UpdateLookRejectsInvalid", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+		id := createPointling(t, repo, 1).PointlingID
+
+		look := models.NewLook()
+		look.Slots["TAIL"] = "tail_1"
+		require.ErrorIs(t, repo.UpdatePointlingLook(ctx, id, look), models.ErrInvalidLook)
+	}},
+	{"LockOutsideTransaction", func(t *testing.T, repo repository.API) {
+		createUser(t, repo, 1, 0)
+		id := createPointling(t, repo, 1).PointlingID
+		_, err := repo.LockPointling(context.Background(), id)
+		require.Error(t, err)
+	}},
+	{"LockInsideTransaction", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+		id := createPointling(t, repo, 1).PointlingID
+
+		err := repo.InTransaction(ctx, func(tx repository.API) error {
+			locked, err := tx.LockPointling(ctx, id)
+			require.NoError(t, err)
+			require.Equal(t, id, locked.PointlingID)
+
+			missing, err := tx.LockPointling(ctx, 404)
+			require.NoError(t, err)
+			require.Nil(t, missing)
+			return nil
+		})
+		require.NoError(t, err)
+	}},
+}
+
+func pointlingIDs(pointlings []*models.Pointling) []int64 {
+	ids := make([]int64, len(pointlings))
+	for i, p := range pointlings {
+		ids[i] = p.PointlingID
+	}
+	return ids
+}
@@ -0,0 +1,355 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository"
+
+	"github.com/stretchr/testify/require"
+)
+
+func spendPoints(t *testing.T, repo repository.API, userID, itemID int64, points int) *models.PointSpend {
+	t.Helper()
+	spend := &models.PointSpend{UserID: userID, ItemID: itemID, PointsSpent: points}
+	require.NoError(t, repo.SpendPoints(context.Background(), spend))
+	return spend
+}
+
+var spendCases = []testCase{
+	{"SpendDebitsLedger", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 100)
+		id := createPointling(t, repo, 1).PointlingID
+		hat := createItem(t, repo, models.Item{Name: "Hat"})
+
+		spend := &models.PointSpend{UserID: 1, PointlingID: &id, ItemID: hat.ItemID, PointsSpent: 30}
+		require.NoError(t, repo.SpendPoints(ctx, spend))
+		require.NotZero(t, spend.SpendID)
+		require.False(t, spend.SpendTS.IsZero())
+
+		user, err := repo.GetUser(ctx, 1)
+		require.NoError(t, err)
+		require.EqualValues(t, 70, user.PointBalance)
+
+		entries, err := repo.GetLedgerEntries(ctx, 1, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.EqualValues(t, -30, entries[0].Delta)
+		require.EqualValues(t, 70, entries[0].BalanceAfter)
+		require.Equal(t, models.LedgerReasonSpend, entries[0].Reason)
+		require.Equal(t, models.SpendReference(spend.SpendID), *entries[0].Reference)
+
+		total, err := repo.GetTotalSpentByUser(ctx, 1, models.TimeRange{})
+		require.NoError(t, err)
+		require.EqualValues(t, 30, total)
+	}},
+	{"SpendInsufficientBalance", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 10)
+		hat := createItem(t, repo, models.Item{Name: "Hat"})
+
+		err := repo.SpendPoints(ctx, &models.PointSpend{UserID: 1, ItemID: hat.ItemID, PointsSpent: 11})
+		require.ErrorIs(t, err, models.ErrInsufficientBalance)
+
+		// The spend record is rolled back with the debit.
+		spends, err := repo.GetByUser(ctx, models.SpendHistoryFilter{UserID: 1, Limit: 10})
+		require.NoError(t, err)
+		require.Empty(t, spends)
+
+		user, err := repo.GetUser(ctx, 1)
+		require.NoError(t, err)
+		require.EqualValues(t, 10, user.PointBalance)
+	}},
+	{"SpendMissingUser", func(t *testing.T, repo repository.API) {
+		hat := createItem(t, repo, models.Item{Name: "Hat"})
+		err := repo.SpendPoints(context.Background(), &models.PointSpend{UserID: 404, ItemID: hat.ItemID, PointsSpent: 1})
+		require.Error(t, err)
+	}},
+	{"CreateSpendWithoutDebit", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 5)
+		hat := createItem(t, repo, models.Item{Name: "Hat"})
+
+		spend := &models.PointSpend{UserID: 1, ItemID: hat.ItemID, PointsSpent: 3}
+		require.NoError(t, repo.CreatePointSpend(ctx, spend))
+		require.NotZero(t, spend.SpendID)
+
+		user, err := repo.GetUser(ctx, 1)
+		require.NoError(t, err)
+		require.EqualValues(t, 5, user.PointBalance)
+	}},
+	{"HistoryNewestFirstWithItems", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 100)
+		createUser(t, repo, 2, 100)
+		hat := createItem(t, repo, models.Item{Name: "Hat"})
+		var spends []*models.PointSpend
+		for i := 1; i <= 3; i++ {
+			spends = append(spends, spendPoints(t, repo, 1, hat.ItemID, i))
+		}
+		spendPoints(t, repo, 2, hat.ItemID, 9)
+
+		page, err := repo.GetByUser(ctx, models.SpendHistoryFilter{UserID: 1, Limit: 2})
+		require.NoError(t, err)
+		require.Equal(t, []int64{spends[2].SpendID, spends[1].SpendID}, spendIDs(page))
+		require.Equal(t, hat, page[0].Item)
+		require.Equal(t, 3, page[0].PointsSpent)
+
+		cursor := models.SpendCursorAfter(page[1])
+		page, err = repo.GetByUser(ctx, models.SpendHistoryFilter{UserID: 1, Limit: 2, Cursor: &cursor})
+		require.NoError(t, err)
+		require.Equal(t, []int64{spends[0].SpendID}, spendIDs(page))
+
+		page, err = repo.GetByUser(ctx, models.SpendHistoryFilter{UserID: 1, Limit: 10, Offset: 2})
+		require.NoError(t, err)
+		require.Equal(t, []int64{spends[0].SpendID}, spendIDs(page))
+
+		page, err = repo.GetByUser(ctx, models.SpendHistoryFilter{UserID: 1, Limit: 10, TimeRange: models.TimeRange{To: &spends[1].SpendTS}})
+		require.NoError(t, err)
+		require.Equal(t, []int64{spends[0].SpendID}, spendIDs(page))
+
+		page, err = repo.GetByUser(ctx, models.SpendHistoryFilter{UserID: 1, Limit: 10, TimeRange: models.TimeRange{From: &spends[1].SpendTS}})
+		require.NoError(t, err)
+		require.Equal(t, []int64{spends[2].SpendID, spends[1].SpendID}, spendIDs(page))
+	}},
+	{"TotalExcludesReversed", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 100)
+		hat := createItem(t, repo, models.Item{Name: "Hat"})
+		spendPoints(t, repo, 1, hat.ItemID, 10)
+		reversed := spendPoints(t, repo, 1, hat.ItemID, 20)
+		require.NoError(t, repo.MarkSpendReversed(ctx, reversed.SpendID))
+
+		total, err := repo.GetTotalSpentByUser(ctx, 1, models.TimeRange{})
+		require.NoError(t, err)
+		require.EqualValues(t, 10, total)
+
+		total, err = repo.GetTotalSpentByUser(ctx, 404, models.TimeRange{})
+		require.NoError(t, err)
+		require.Zero(t, total)
+	}},
+	{"TotalWithinRange", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 100)
+		hat := createItem(t, repo, models.Item{Name: "Hat"})
+		var spends []*models.PointSpend
+		for i := 1; i <= 3; i++ {
+			spends = append(spends, spendPoints(t, repo, 1, hat.ItemID, i))
+		}
+
+		total, err := repo.GetTotalSpentByUser(ctx, 1, models.TimeRange{From: &spends[1].SpendTS})
+		require.NoError(t, err)
+		require.EqualValues(t, 5, total)
+
+		total, err = repo.GetTotalSpentByUser(ctx, 1, models.TimeRange{To: &spends[1].SpendTS})
+		require.NoError(t, err)
+		require.EqualValues(t, 1, total)
+	}},
+}
+
+var ledgerCases = []testCase{
+	{"PostUpdatesBalance", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+
+		entry := &models.LedgerEntry{UserID: 1, Delta: 50, Reason: models.LedgerReasonReceipt, Reference: ptr("receipt:1")}
+		require.NoError(t, repo.PostLedgerEntry(ctx, entry))
+		require.NotZero(t, entry.EntryID)
+		require.EqualValues(t, 50, entry.BalanceAfter)
+		require.Equal(t, models.LedgerReasonReceipt.CounterAccount(), entry.CounterAccount)
+		require.False(t, entry.CreatedAt.IsZero())
+
+		require.NoError(t, repo.PostLedgerEntry(ctx, &models.LedgerEntry{UserID: 1, Delta: -50, Reason: models.LedgerReasonAdminAdjustment}))
+
+		user, err := repo.GetUser(ctx, 1)
+		require.NoError(t, err)
+		require.Zero(t, user.PointBalance)
+	}},
+	{"PostRejections", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 10)
+
+		err := repo.PostLedgerEntry(ctx, &models.LedgerEntry{UserID: 404, Delta: 5, Reason: models.LedgerReasonPromo})
+		require.ErrorIs(t, err, models.ErrUserNotFound)
+
+		err = repo.PostLedgerEntry(ctx, &models.LedgerEntry{UserID: 1, Delta: -11, Reason: models.LedgerReasonAdminAdjustment})
+		require.ErrorIs(t, err, models.ErrInsufficientBalance)
+
+		err = repo.PostLedgerEntry(ctx, &models.LedgerEntry{UserID: 1, Delta: 5, Reason: models.LedgerReasonSpend})
+		require.ErrorIs(t, err, models.ErrInvalidLedgerEntry)
+
+		err = repo.PostLedgerEntry(ctx, &models.LedgerEntry{UserID: 1, Delta: 0, Reason: models.LedgerReasonPromo})
+		require.ErrorIs(t, err, models.ErrInvalidLedgerEntry)
+
+		user, err := repo.GetUser(ctx, 1)
+		require.NoError(t, err)
+		require.EqualValues(t, 10, user.PointBalance)
+	}},
+	{"PostDuplicateReference", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+
+		require.NoError(t, repo.PostLedgerEntry(ctx, &models.LedgerEntry{UserID: 1, Delta: 5, Reason: models.LedgerReasonReceipt, Reference: ptr("receipt:1")}))
+		err := repo.PostLedgerEntry(ctx, &models.LedgerEntry{UserID: 1, Delta: 5, Reason: models.LedgerReasonReceipt, Reference: ptr("receipt:1")})
+		require.ErrorIs(t, err, models.ErrDuplicateLedgerEntry)
+
+		// The same reference under another reason, or no reference, is not a duplicate.
+		require.NoError(t, repo.PostLedgerEntry(ctx, &models.LedgerEntry{UserID: 1, Delta: 5, Reason: models.LedgerReasonPromo, Reference: ptr("receipt:1")}))
+		require.NoError(t, repo.PostLedgerEntry(ctx, &models.LedgerEntry{UserID: 1, Delta: 5, Reason: models.LedgerReasonPromo}))
+		require.NoError(t, repo.PostLedgerEntry(ctx, &models.LedgerEntry{UserID: 1, Delta: 5, Reason: models.LedgerReasonPromo}))
+
+		user, err := repo.GetUser(ctx, 1)
+		require.NoError(t, err)
+		require.EqualValues(t, 20, user.PointBalance)
+	}},
+	{"EntriesNewestFirstAndBalance", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+		createUser(t, repo, 2, 0)
+		var ids []int64
+		for i := int64(1); i <= 3; i++ {
+			entry := &models.LedgerEntry{UserID: 1, Delta: i, Reason: models.LedgerReasonPromo}
+			require.NoError(t, repo.PostLedgerEntry(ctx, entry))
+			ids = append(ids, entry.EntryID)
+		}
+		require.NoError(t, repo.PostLedgerEntry(ctx, &models.LedgerEntry{UserID: 2, Delta: 9, Reason: models.LedgerReasonPromo}))
+
+		entries, err := repo.GetLedgerEntries(ctx, 1, 0, 2)
+		require.NoError(t, err)
+		require.Equal(t, []int64{ids[2], ids[1]}, entryIDs(entries))
+
+		entries, err = repo.GetLedgerEntries(ctx, 1, ids[1], 10)
+		require.NoError(t, err)
+		require.Equal(t, []int64{ids[0]}, entryIDs(entries))
+
+		balance, err := repo.GetLedgerBalance(ctx, 1)
+		require.NoError(t, err)
+		require.EqualValues(t, 6, balance)
+
+		balance, err = repo.GetLedgerBalance(ctx, 404)
+		require.NoError(t, err)
+		require.Zero(t, balance)
+	}},
+	{"BackfillOpeningBalances", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 40)
+		createUser(t, repo, 2, 0)
+		createUser(t, repo, 3, 10)
+		require.NoError(t, repo.PostLedgerEntry(ctx, &models.LedgerEntry{UserID: 3, Delta: 5, Reason: models.LedgerReasonPromo}))
+
+		n, err := repo.BackfillOpeningBalances(ctx)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, n)
+
+		entries, err := repo.GetLedgerEntries(ctx, 1, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Equal(t, models.LedgerReasonOpeningBalance, entries[0].Reason)
+		require.EqualValues(t, 40, entries[0].Delta)
+		require.EqualValues(t, 40, entries[0].BalanceAfter)
+		require.Nil(t, entries[0].Reference)
+
+		n, err = repo.BackfillOpeningBalances(ctx)
+		require.NoError(t, err)
+		require.Zero(t, n)
+	}},
+}
+
+var refundCases = []testCase{
+	{"LockOutsideTransaction", func(t *testing.T, repo repository.API) {
+		_, err := repo.LockPointSpend(context.Background(), 1)
+		require.Error(t, err)
+	}},
+	{"LockInsideTransaction", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 10)
+		hat := createItem(t, repo, models.Item{Name: "Hat"})
+		spend := spendPoints(t, repo, 1, hat.ItemID, 4)
+
+		err := repo.InTransaction(ctx, func(tx repository.API) error {
+			locked, err := tx.LockPointSpend(ctx, spend.SpendID)
+			require.NoError(t, err)
+			require.Equal(t, spend.SpendID, locked.SpendID)
+			require.Equal(t, 4, locked.PointsSpent)
+			require.Nil(t, locked.ReversedAt)
+
+			missing, err := tx.LockPointSpend(ctx, 404)
+			require.NoError(t, err)
+			require.Nil(t, missing)
+			return nil
+		})
+		require.NoError(t, err)
+	}},
+	{"MarkReversedOnce", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 10)
+		hat := createItem(t, repo, models.Item{Name: "Hat"})
+		spend := spendPoints(t, repo, 1, hat.ItemID, 4)
+
+		require.NoError(t, repo.MarkSpendReversed(ctx, spend.SpendID))
+		require.ErrorIs(t, repo.MarkSpendReversed(ctx, spend.SpendID), models.ErrSpendNotFound)
+		require.ErrorIs(t, repo.MarkSpendReversed(ctx, 404), models.ErrSpendNotFound)
+
+		page, err := repo.GetByUser(ctx, models.SpendHistoryFilter{UserID: 1, Limit: 10})
+		require.NoError(t, err)
+		require.NotNil(t, page[0].ReversedAt)
+	}},
+	{"Reversal", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 10)
+		id := createPointling(t, repo, 1).PointlingID
+		hat := createItem(t, repo, models.Item{Name: "Hat"})
+		spend := &models.PointSpend{UserID: 1, PointlingID: &id, ItemID: hat.ItemID, PointsSpent: 4}
+		require.NoError(t, repo.SpendPoints(ctx, spend))
+		refund := &models.LedgerEntry{UserID: 1, Delta: 4, Reason: models.LedgerReasonRefund, Reference: ptr(models.SpendReference(spend.SpendID))}
+		require.NoError(t, repo.PostLedgerEntry(ctx, refund))
+
+		reversal := &models.SpendReversal{
+			SpendID:        spend.SpendID,
+			UserID:         1,
+			PointlingID:    &id,
+			ItemID:         hat.ItemID,
+			PointsRefunded: 4,
+			LedgerEntryID:  refund.EntryID,
+			ItemRevoked:    true,
+			Reason:         "duplicate purchase",
+			Actor:          "api-key:support",
+		}
+		require.NoError(t, repo.CreateSpendReversal(ctx, reversal))
+		require.False(t, reversal.ReversedAt.IsZero())
+
+		got, err := repo.GetSpendReversal(ctx, spend.SpendID)
+		require.NoError(t, err)
+		require.True(t, reversal.ReversedAt.Equal(got.ReversedAt))
+		got.ReversedAt = reversal.ReversedAt
+		require.Equal(t, reversal, got)
+
+		require.Error(t, repo.CreateSpendReversal(ctx, reversal))
+
+		missing, err := repo.GetSpendReversal(ctx, 404)
+		require.NoError(t, err)
+		require.Nil(t, missing)
+	}},
+}
+
+func spendIDs(spends []*models.PointSpend) []int64 {
+	ids := make([]int64, len(spends))
+	for i, s := range spends {
+		ids[i] = s.SpendID
+	}
+	return ids
+}
+
+func entryIDs(entries []*models.LedgerEntry) []int64 {
+	ids := make([]int64, len(entries))
+	for i, e := range entries {
+		ids[i] = e.EntryID
+	}
+	return ids
+}
+
+// past is a time safely before anything the suite creates.
+var past = time.Now().Add(-48 * time.Hour)
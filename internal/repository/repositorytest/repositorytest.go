@@ -0,0 +1,98 @@
+// Package repositorytest is a conformance suite for repository.API
+// implementations. Every backend runs the same cases, so the in-memory store
+// stays a faithful stand-in for Postgres.
+package repositorytest
+
+import (
+	"context"
+	"testing"
+
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Factory returns an empty repository. It is called once per case.
+type Factory func(t *testing.T) repository.API
+
+// PersonalitySeeder is implemented by backends that can load the personality
+// catalog, which repository.API has no method to write. Cases that need
+// personalities are skipped for backends without it.
+type PersonalitySeeder interface {
+	SeedPersonalities(personalities ...models.Personality)
+}
+
+type testCase struct {
+	name string
+	run  func(t *testing.T, repo repository.API)
+}
+
+// Run runs every conformance case against repositories from newRepo.
+func Run(t *testing.T, newRepo Factory) {
+	groups := []struct {
+		name  string
+		cases []testCase
+	}{
+		{"Transactions", transactionCases},
+		{"Users", userCases},
+		{"Pointlings", pointlingCases},
+		{"XP", xpCases},
+		{"XPConfig", xpConfigCases},
+		{"Personalities", personalityCases},
+		{"Items", itemCases},
+		{"Inventory", inventoryCases},
+		{"Spends", spendCases},
+		{"Ledger", ledgerCases},
+		{"Refunds", refundCases},
+		{"Rewards", rewardCases},
+		{"Colors", colorCases},
+		{"Idempotency", idempotencyCases},
+		{"Audit", auditCases},
+	}
+	for _, group := range groups {
+		t.Run(group.name, func(t *testing.T) {
+			for _, tc := range group.cases {
+				t.Run(tc.name, func(t *testing.T) {
+					tc.run(t, newRepo(t))
+				})
+			}
+		})
+	}
+}
+
+func createUser(t *testing.T, repo repository.API, userID, balance int64) *models.User {
+	t.Helper()
+	user := &models.User{UserID: userID, DisplayName: "user", PointBalance: balance}
+	require.NoError(t, repo.CreateUser(context.Background(), user))
+	return user
+}
+
+func createPointling(t *testing.T, repo repository.API, userID int64) *models.Pointling {
+	t.Helper()
+	pointling := models.NewPointling(userID, nil)
+	require.NoError(t, repo.CreatePointling(context.Background(), pointling))
+	return pointling
+}
+
+func createItem(t *testing.T, repo repository.API, item models.Item) *models.Item {
+	t.Helper()
+	if item.Category == "" {
+		item.Category = models.CategoryAccessory
+	}
+	if item.Category == models.CategoryAccessory && item.Slot == nil {
+		item.Slot = ptr(models.SlotHat)
+	}
+	if item.Rarity == "" {
+		item.Rarity = models.RarityCommon
+	}
+	if item.AssetID == "" {
+		item.AssetID = item.Name
+	}
+	require.NoError(t, repo.CreateItem(context.Background(), &item))
+	return &item
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,220 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository"
+
+	"github.com/stretchr/testify/require"
+)
+
+func offerReward(t *testing.T, repo repository.API, pointlingID int64, level int, expiresAt time.Time) *models.LevelReward {
+	t.Helper()
+	reward := &models.LevelReward{
+		PointlingID: pointlingID,
+		Level:       level,
+		Options: []models.RewardOption{
+			{Type: models.RewardTypeColor, ID: "#FF9F45"},
+			{Type: models.RewardTypeItem, ID: "12"},
+		},
+		ExpiresAt: expiresAt,
+	}
+	require.NoError(t, repo.CreateLevelReward(context.Background(), reward))
+	return reward
+}
+
+var rewardCases = []testCase{
+	{"CreateAndGet", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+		id := createPointling(t, repo, 1).PointlingID
+		reward := offerReward(t, repo, id, 2, time.Now().Add(time.Hour))
+		require.NotZero(t, reward.RewardID)
+		require.False(t, reward.OfferedAt.IsZero())
+
+		got, err := repo.GetLevelReward(ctx, reward.RewardID)
+		require.NoError(t, err)
+		require.Equal(t, id, got.PointlingID)
+		require.Equal(t, 2, got.Level)
+		require.Equal(t, reward.Options, got.Options)
+		require.Nil(t, got.ChosenOption)
+		require.Nil(t, got.ClaimedAt)
+
+		missing, err := repo.GetLevelReward(ctx, 404)
+		require.NoError(t, err)
+		require.Nil(t, missing)
+	}},
+	{"CreateDuplicateLevel", func(t *testing.T, repo repository.API) {
+		createUser(t, repo, 1, 0)
+		id := createPointling(t, repo, 1).PointlingID
+		offerReward(t, repo, id, 2, time.Now().Add(time.Hour))
+
+		err := repo.CreateLevelReward(context.Background(), &models.LevelReward{
+			PointlingID: id, Level: 2, Options: []models.RewardOption{}, ExpiresAt: time.Now().Add(time.Hour),
+		})
+		require.Error(t, err)
+	}},
+	{"PendingExcludesClaimedAndExpired", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+		id := createPointling(t, repo, 1).PointlingID
+		later := time.Now().Add(time.Hour)
+		level4 := offerReward(t, repo, id, 4, later)
+		level2 := offerReward(t, repo, id, 2, later)
+		claimed := offerReward(t, repo, id, 3, later)
+		offerReward(t, repo, id, 5, past)
+		require.NoError(t, repo.ClaimLevelReward(ctx, claimed.RewardID, claimed.Options[0]))
+
+		pending, err := repo.GetPendingRewards(ctx, id)
+		require.NoError(t, err)
+		require.Len(t, pending, 2)
+		require.Equal(t, level2.RewardID, pending[0].RewardID)
+		require.Equal(t, level4.RewardID, pending[1].RewardID)
+
+		pending, err = repo.GetPendingRewards(ctx, 404)
+		require.NoError(t, err)
+		require.Empty(t, pending)
+	}},
+	{"ClaimOnce", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+		id := createPointling(t, repo, 1).PointlingID
+		reward := offerReward(t, repo, id, 2, time.Now().Add(time.Hour))
+
+		require.NoError(t, repo.ClaimLevelReward(ctx, reward.RewardID, reward.Options[1]))
+		err := repo.ClaimLevelReward(ctx, reward.RewardID, reward.Options[0])
+		require.ErrorIs(t, err, models.ErrRewardAlreadyClaimed)
+
+		got, err := repo.GetLevelReward(ctx, reward.RewardID)
+		require.NoError(t, err)
+		require.Equal(t, reward.Options[1], *got.ChosenOption)
+		require.NotNil(t, got.ClaimedAt)
+	}},
+	{"ClaimExpiredOrMissing", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+		id := createPointling(t, repo, 1).PointlingID
+		expired := offerReward(t, repo, id, 2, past)
+
+		err := repo.ClaimLevelReward(ctx, expired.RewardID, expired.Options[0])
+		require.ErrorIs(t, err, models.ErrRewardAlreadyClaimed)
+		err = repo.ClaimLevelReward(ctx, 404, expired.Options[0])
+		require.ErrorIs(t, err, models.ErrRewardAlreadyClaimed)
+	}},
+}
+
+var colorCases = []testCase{
+	{"AddAndListInOrder", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+		id := createPointling(t, repo, 1).PointlingID
+		other := createPointling(t, repo, 1).PointlingID
+
+		require.NoError(t, repo.AddPointlingColor(ctx, id, "#FF8FA3"))
+		require.NoError(t, repo.AddPointlingColor(ctx, id, "#4ECDC4"))
+		require.NoError(t, repo.AddPointlingColor(ctx, other, "#7BD389"))
+
+		colors, err := repo.GetPointlingColors(ctx, id)
+		require.NoError(t, err)
+		require.Len(t, colors, 2)
+		require.Equal(t, "#FF8FA3", colors[0].ColorHex)
+		require.Equal(t, "#4ECDC4", colors[1].ColorHex)
+		require.Equal(t, id, colors[0].PointlingID)
+		require.False(t, colors[0].AcquiredAt.IsZero())
+
+		colors, err = repo.GetPointlingColors(ctx, 404)
+		require.NoError(t, err)
+		require.Empty(t, colors)
+	}},
+	{"AddDuplicate", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+		id := createPointling(t, repo, 1).PointlingID
+
+		require.NoError(t, repo.AddPointlingColor(ctx, id, "#FF8FA3"))
+		require.ErrorIs(t, repo.AddPointlingColor(ctx, id, "#FF8FA3"), models.ErrAlreadyOwned)
+	}},
+}
+
+var idempotencyCases = []testCase{
+	{"ReserveOnce", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		record, reserved, err := repo.ReserveIdempotencyKey(ctx, "key-1", "hash-a", time.Hour)
+		require.NoError(t, err)
+		require.True(t, reserved)
+		require.Equal(t, "key-1", record.Key)
+		require.Equal(t, "hash-a", record.RequestHash)
+		require.Nil(t, record.StatusCode)
+		require.True(t, record.ExpiresAt.After(record.CreatedAt))
+
+		existing, reserved, err := repo.ReserveIdempotencyKey(ctx, "key-1", "hash-b", time.Hour)
+		require.NoError(t, err)
+		require.False(t, reserved)
+		require.Equal(t, "hash-a", existing.RequestHash)
+	}},
+	{"SaveAndReplay", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		_, _, err := repo.ReserveIdempotencyKey(ctx, "key-1", "hash-a", time.Hour)
+		require.NoError(t, err)
+		require.NoError(t, repo.SaveIdempotentResponse(ctx, "key-1", 201, "application/json", []byte(`{"ok":true}`)))
+
+		record, err := repo.GetIdempotencyKey(ctx, "key-1")
+		require.NoError(t, err)
+		require.Equal(t, 201, *record.StatusCode)
+		require.Equal(t, "application/json", record.ContentType)
+		require.Equal(t, []byte(`{"ok":true}`), record.ResponseBody)
+
+		existing, reserved, err := repo.ReserveIdempotencyKey(ctx, "key-1", "hash-a", time.Hour)
+		require.NoError(t, err)
+		require.False(t, reserved)
+		require.Equal(t, 201, *existing.StatusCode)
+	}},
+	{"MissingKey", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		record, err := repo.GetIdempotencyKey(ctx, "nope")
+		require.NoError(t, err)
+		require.Nil(t, record)
+
+		require.Error(t, repo.SaveIdempotentResponse(ctx, "nope", 200, "", nil))
+		require.NoError(t, repo.ReleaseIdempotencyKey(ctx, "nope"))
+	}},
+	{"ReleaseFreesKey", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		_, _, err := repo.ReserveIdempotencyKey(ctx, "key-1", "hash-a", time.Hour)
+		require.NoError(t, err)
+		require.NoError(t, repo.ReleaseIdempotencyKey(ctx, "key-1"))
+
+		_, reserved, err := repo.ReserveIdempotencyKey(ctx, "key-1", "hash-b", time.Hour)
+		require.NoError(t, err)
+		require.True(t, reserved)
+	}},
+	{"ExpiredKeys", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		_, _, err := repo.ReserveIdempotencyKey(ctx, "expired", "hash-a", 0)
+		require.NoError(t, err)
+		_, _, err = repo.ReserveIdempotencyKey(ctx, "stale", "hash-a", 0)
+		require.NoError(t, err)
+		_, _, err = repo.ReserveIdempotencyKey(ctx, "live", "hash-a", time.Hour)
+		require.NoError(t, err)
+
+		// An expired key is taken over by the next request.
+		record, reserved, err := repo.ReserveIdempotencyKey(ctx, "expired", "hash-b", time.Hour)
+		require.NoError(t, err)
+		require.True(t, reserved)
+		require.Equal(t, "hash-b", record.RequestHash)
+
+		purged, err := repo.PurgeExpiredIdempotencyKeys(ctx)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, purged)
+
+		record, err = repo.GetIdempotencyKey(ctx, "stale")
+		require.NoError(t, err)
+		require.Nil(t, record)
+		record, err = repo.GetIdempotencyKey(ctx, "live")
+		require.NoError(t, err)
+		require.NotNil(t, record)
+	}},
+}
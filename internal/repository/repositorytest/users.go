@@ -0,0 +1,203 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errRollback = errors.New("rollback")
+
+var transactionCases = []testCase{
+	{"CommitsOnSuccess", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		err := repo.InTransaction(ctx, func(tx repository.API) error {
+			return tx.CreateUser(ctx, &models.User{UserID: 1, DisplayName: "a"})
+		})
+		require.NoError(t, err)
+
+		user, err := repo.GetUser(ctx, 1)
+		require.NoError(t, err)
+		require.NotNil(t, user)
+	}},
+	{"RollsBackOnError", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 100)
+
+		err := repo.InTransaction(ctx, func(tx repository.API) error {
+			require.NoError(t, tx.CreateUser(ctx, &models.User{UserID: 2, DisplayName: "b"}))
+			require.NoError(t, tx.UpdateUserTimezone(ctx, 1, "Europe/Berlin"))
+			require.NoError(t, tx.PostLedgerEntry(ctx, &models.LedgerEntry{
+				UserID: 1, Delta: -40, Reason: models.LedgerReasonAdminAdjustment,
+			}))
+			return errRollback
+		})
+		require.ErrorIs(t, err, errRollback)
+
+		created, err := repo.GetUser(ctx, 2)
+		require.NoError(t, err)
+		require.Nil(t, created)
+
+		user, err := repo.GetUser(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, models.DefaultTimezone, user.Timezone)
+		require.EqualValues(t, 100, user.PointBalance)
+
+		entries, err := repo.GetLedgerEntries(ctx, 1, 0, 10)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	}},
+	{"SeesOwnWrites", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		err := repo.InTransaction(ctx, func(tx repository.API) error {
+			require.NoError(t, tx.CreateUser(ctx, &models.User{UserID: 1, DisplayName: "a"}))
+			user, err := tx.GetUser(ctx, 1)
+			require.NoError(t, err)
+			require.NotNil(t, user)
+			return nil
+		})
+		require.NoError(t, err)
+	}},
+	{"NestedRollsBackWithOuter", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		var pointlingID int64
+		err := repo.InTransaction(ctx, func(tx repository.API) error {
+			require.NoError(t, tx.CreateUser(ctx, &models.User{UserID: 1, DisplayName: "a"}))
+			// The inner call sees the outer's uncommitted user, which a
+			// separate transaction could not reference.
+			require.NoError(t, tx.InTransaction(ctx, func(inner repository.API) error {
+				p := models.NewPointling(1, nil)
+				if err := inner.CreatePointling(ctx, p); err != nil {
+					return err
+				}
+				pointlingID = p.PointlingID
+				return nil
+			}))
+			return errRollback
+		})
+		require.ErrorIs(t, err, errRollback)
+
+		// The inner call's writes went down with the outer transaction.
+		user, err := repo.GetUser(ctx, 1)
+		require.NoError(t, err)
+		require.Nil(t, user)
+		p, err := repo.GetPointlingByID(ctx, pointlingID)
+		require.NoError(t, err)
+		require.Nil(t, p)
+	}},
+	{"NestedErrorRollsBackAll", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 100)
+
+		err := repo.InTransaction(ctx, func(tx repository.API) error {
+			require.NoError(t, tx.UpdateUserTimezone(ctx, 1, "Europe/Berlin"))
+			return tx.InTransaction(ctx, func(inner repository.API) error {
+				require.NoError(t, inner.PostLedgerEntry(ctx, &models.LedgerEntry{
+					UserID: 1, Delta: -40, Reason: models.LedgerReasonAdminAdjustment,
+				}))
+				return errRollback
+			})
+		})
+		require.ErrorIs(t, err, errRollback)
+
+		user, err := repo.GetUser(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, models.DefaultTimezone, user.Timezone)
+		require.EqualValues(t, 100, user.PointBalance)
+	}},
+	{"NestedCommitsWithOuter", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		err := repo.InTransaction(ctx, func(tx repository.API) error {
+			require.NoError(t, tx.CreateUser(ctx, &models.User{UserID: 1, DisplayName: "a"}))
+			return tx.InTransaction(ctx, func(inner repository.API) error {
+				return inner.CreatePointling(ctx, models.NewPointling(1, nil))
+			})
+		})
+		require.NoError(t, err)
+
+		pointlings, err := repo.GetPointlingByUserID(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, pointlings, 1)
+	}},
+	{"CancelledContext", func(t *testing.T, repo repository.API) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := repo.GetUser(ctx, 1)
+		require.ErrorIs(t, err, context.Canceled)
+
+		err = repo.InTransaction(ctx, func(tx repository.API) error { return nil })
+		require.ErrorIs(t, err, context.Canceled)
+	}},
+}
+
+var userCases = []testCase{
+	{"CreateAndGet", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		user := &models.User{UserID: 7, DisplayName: "seven", PointBalance: 25}
+		require.NoError(t, repo.CreateUser(ctx, user))
+		require.Equal(t, models.DefaultTimezone, user.Timezone)
+
+		got, err := repo.GetUser(ctx, 7)
+		require.NoError(t, err)
+		require.Equal(t, int64(7), got.UserID)
+		require.Equal(t, "seven", got.DisplayName)
+		require.EqualValues(t, 25, got.PointBalance)
+		require.Equal(t, models.DefaultTimezone, got.Timezone)
+		require.False(t, got.CreatedAt.IsZero())
+	}},
+	{"GetMissing", func(t *testing.T, repo repository.API) {
+		user, err := repo.GetUser(context.Background(), 404)
+		require.NoError(t, err)
+		require.Nil(t, user)
+	}},
+	{"CreateDuplicate", func(t *testing.T, repo repository.API) {
+		createUser(t, repo, 1, 0)
+		err := repo.CreateUser(context.Background(), &models.User{UserID: 1, DisplayName: "again"})
+		require.Error(t, err)
+	}},
+	{"UpdateTimezone", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+		require.NoError(t, repo.UpdateUserTimezone(ctx, 1, "Asia/Tokyo"))
+
+		user, err := repo.GetUser(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, "Asia/Tokyo", user.Timezone)
+	}},
+	{"UpdateTimezoneMissing", func(t *testing.T, repo repository.API) {
+		err := repo.UpdateUserTimezone(context.Background(), 404, "UTC")
+		require.ErrorIs(t, err, models.ErrUserNotFound)
+	}},
+	{"ListNewestFirstWithPaging", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		for id := int64(1); id <= 3; id++ {
+			createUser(t, repo, id, 0)
+		}
+
+		users, err := repo.ListUsers(ctx, 10, 0)
+		require.NoError(t, err)
+		require.Equal(t, []int64{3, 2, 1}, userIDs(users))
+
+		users, err = repo.ListUsers(ctx, 1, 1)
+		require.NoError(t, err)
+		require.Equal(t, []int64{2}, userIDs(users))
+
+		users, err = repo.ListUsers(ctx, 10, 3)
+		require.NoError(t, err)
+		require.Empty(t, users)
+	}},
+}
+
+func userIDs(users []*models.User) []int64 {
+	ids := make([]int64, len(users))
+	for i, u := range users {
+		ids[i] = u.UserID
+	}
+	return ids
+}
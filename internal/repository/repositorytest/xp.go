@@ -0,0 +1,307 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository"
+
+	"github.com/stretchr/testify/require"
+)
+
+// todayCap is a generous cap over the current UTC day.
+func todayCap(max int) models.XPDailyCap {
+	return models.XPDailyCap{Window: models.DailyWindow(time.Now(), time.UTC), Max: max}
+}
+
+// zoneCap is a cap over the current day in the named timezone.
+func zoneCap(t *testing.T, zone string, max int) models.XPDailyCap {
+	t.Helper()
+	loc, err := time.LoadLocation(zone)
+	require.NoError(t, err)
+	return models.XPDailyCap{Window: models.DailyWindow(time.Now(), loc), Max: max}
+}
+
+func addXP(t *testing.T, repo repository.API, pointlingID int64, source models.XPEventSource, amount int) *models.XPEvent {
+	t.Helper()
+	event := &models.XPEvent{PointlingID: pointlingID, Source: source, XPAmount: amount}
+	require.NoError(t, repo.AddXP(context.Background(), event, todayCap(1000)))
+	return event
+}
+
+var xpCases = []testCase{
+	{"AddRecordsEventOnly", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+		id := createPointling(t, repo, 1).PointlingID
+
+		event := addXP(t, repo, id, models.XPSourcePlay, 7)
+		require.NotZero(t, event.EventID)
+		require.False(t, event.EventTS.IsZero())
+		daily := addXP(t, repo, id, models.XPSourceDaily, 5)
+
+		page, err := repo.GetEventsByPointling(ctx, models.XPHistoryFilter{PointlingID: id, Limit: 10})
+		require.NoError(t, err)
+		require.Equal(t, []int64{daily.EventID, event.EventID}, eventIDs(page))
+
+		// Applying the gain to the pointling is left to the caller.
+		got, err := repo.GetPointlingByID(ctx, id)
+		require.NoError(t, err)
+		require.Zero(t, got.CurrentXP)
+	}},
+	{"AddRespectsDailyCap", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+		id := createPointling(t, repo, 1).PointlingID
+		limit := todayCap(10)
+
+		require.NoError(t, repo.AddXP(ctx, &models.XPEvent{PointlingID: id, Source: models.XPSourcePlay, XPAmount: 6}, limit))
+		// The cap is per source.
+		require.NoError(t, repo.AddXP(ctx, &models.XPEvent{PointlingID: id, Source: models.XPSourceDaily, XPAmount: 5}, limit))
+		// Exactly reaching the cap is allowed.
+		require.NoError(t, repo.AddXP(ctx, &models.XPEvent{PointlingID: id, Source: models.XPSourcePlay, XPAmount: 4}, limit))
+		err := repo.AddXP(ctx, &models.XPEvent{PointlingID: id, Source: models.XPSourcePlay, XPAmount: 1}, limit)
+		require.ErrorIs(t, err, models.ErrDailyXPLimitReached)
+
+		earned, err := repo.GetDailyXPBySource(ctx, id, models.XPSourcePlay, limit.Window)
+		require.NoError(t, err)
+		require.Equal(t, 10, earned)
+	}},
+	{"AddTrimsGainAtDailyCap", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+		id := createPointling(t, repo, 1).PointlingID
+		limit := todayCap(10)
+
+		require.NoError(t, repo.AddXP(ctx, &models.XPEvent{PointlingID: id, Source: models.XPSourcePlay, XPAmount: 6}, limit))
+		// The cap is reached partway through this gain: only the rest is granted.
+		event := &models.XPEvent{PointlingID: id, Source: models.XPSourcePlay, XPAmount: 5}
+		require.NoError(t, repo.AddXP(ctx, event, limit))
+		require.Equal(t, 4, event.XPAmount)
+
+		page, err := repo.GetEventsByPointling(ctx, models.XPHistoryFilter{PointlingID: id, Limit: 1})
+		require.NoError(t, err)
+		require.Equal(t, 4, page[0].XPAmount)
+		earned, err := repo.GetDailyXPBySource(ctx, id, models.XPSourcePlay, limit.Window)
+		require.NoError(t, err)
+		require.Equal(t, 10, earned)
+
+		err = repo.AddXP(ctx, &models.XPEvent{PointlingID: id, Source: models.XPSourcePlay, XPAmount: 5}, limit)
+		require.ErrorIs(t, err, models.ErrDailyXPLimitReached)
+	}},
+	{"DailyCapInUserZone", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+		id := createPointling(t, repo, 1).PointlingID
+		// UTC+14 and UTC-11 are always on different calendar days, but both
+		// local days contain the present.
+		east := zoneCap(t, "Pacific/Kiritimati", 10)
+		west := zoneCap(t, "Pacific/Pago_Pago", 10)
+
+		require.NoError(t, repo.AddXP(ctx, &models.XPEvent{PointlingID: id, Source: models.XPSourcePlay, XPAmount: 10}, east))
+		err := repo.AddXP(ctx, &models.XPEvent{PointlingID: id, Source: models.XPSourcePlay, XPAmount: 1}, west)
+		require.ErrorIs(t, err, models.ErrDailyXPLimitReached, "the gain falls inside both zones' today")
+
+		// The previous local day has its own, untouched cap.
+		yesterday := models.XPDailyCap{
+			Window: models.DayWindow{Start: east.Window.Start.AddDate(0, 0, -1), End: east.Window.Start},
+			Max:    10,
+		}
+		earned, err := repo.GetDailyXPBySource(ctx, id, models.XPSourcePlay, yesterday.Window)
+		require.NoError(t, err)
+		require.Zero(t, earned)
+
+		// Windows are half-open: a day ending at the gain does not count it.
+		page, err := repo.GetEventsByPointling(ctx, models.XPHistoryFilter{PointlingID: id, Limit: 1})
+		require.NoError(t, err)
+		ts := page[0].EventTS
+		earned, err = repo.GetDailyXPBySource(ctx, id, models.XPSourcePlay, models.DayWindow{Start: ts.Add(-time.Hour), End: ts})
+		require.NoError(t, err)
+		require.Zero(t, earned)
+		earned, err = repo.GetDailyXPBySource(ctx, id, models.XPSourcePlay, models.DayWindow{Start: ts, End: ts.Add(time.Hour)})
+		require.NoError(t, err)
+		require.Equal(t, 10, earned)
+	}},
+	{"AddForMissingPointling", func(t *testing.T, repo repository.API) {
+		err := repo.AddXP(context.Background(), &models.XPEvent{PointlingID: 404, Source: models.XPSourcePlay, XPAmount: 1}, todayCap(10))
+		require.ErrorIs(t, err, models.ErrPointlingNotFound)
+	}},
+	{"DailyXPBySourceWindow", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+		id := createPointling(t, repo, 1).PointlingID
+		addXP(t, repo, id, models.XPSourcePlay, 3)
+
+		today := todayCap(0).Window
+		earned, err := repo.GetDailyXPBySource(ctx, id, models.XPSourcePlay, today)
+		require.NoError(t, err)
+		require.Equal(t, 3, earned)
+
+		yesterday := models.DayWindow{Start: today.Start.Add(-24 * time.Hour), End: today.Start}
+		earned, err = repo.GetDailyXPBySource(ctx, id, models.XPSourcePlay, yesterday)
+		require.NoError(t, err)
+		require.Zero(t, earned)
+
+		earned, err = repo.GetDailyXPBySource(ctx, id, models.XPSourceReceipt, today)
+		require.NoError(t, err)
+		require.Zero(t, earned)
+	}},
+	{"EventsNewestFirstWithCursor", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+		id := createPointling(t, repo, 1).PointlingID
+		other := createPointling(t, repo, 1).PointlingID
+		var events []*models.XPEvent
+		for i := 1; i <= 3; i++ {
+			events = append(events, addXP(t, repo, id, models.XPSourcePlay, i))
+		}
+		addXP(t, repo, other, models.XPSourcePlay, 9)
+
+		page, err := repo.GetEventsByPointling(ctx, models.XPHistoryFilter{PointlingID: id, Limit: 2})
+		require.NoError(t, err)
+		require.Equal(t, []int64{events[2].EventID, events[1].EventID}, eventIDs(page))
+		require.Equal(t, 3, page[0].XPAmount)
+		require.Equal(t, models.XPSourcePlay, page[0].Source)
+
+		cursor := models.CursorAfter(page[1])
+		page, err = repo.GetEventsByPointling(ctx, models.XPHistoryFilter{PointlingID: id, Limit: 2, Cursor: &cursor})
+		require.NoError(t, err)
+		require.Equal(t, []int64{events[0].EventID}, eventIDs(page))
+	}},
+	{"EventsFiltered", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+		id := createPointling(t, repo, 1).PointlingID
+		play := addXP(t, repo, id, models.XPSourcePlay, 1)
+		addXP(t, repo, id, models.XPSourceDaily, 2)
+
+		page, err := repo.GetEventsByPointling(ctx, models.XPHistoryFilter{PointlingID: id, Source: ptr(models.XPSourcePlay), Limit: 10})
+		require.NoError(t, err)
+		require.Equal(t, []int64{play.EventID}, eventIDs(page))
+
+		future := time.Now().Add(time.Hour)
+		page, err = repo.GetEventsByPointling(ctx, models.XPHistoryFilter{PointlingID: id, TimeRange: models.TimeRange{From: &future}, Limit: 10})
+		require.NoError(t, err)
+		require.Empty(t, page)
+
+		page, err = repo.GetEventsByPointling(ctx, models.XPHistoryFilter{PointlingID: id, TimeRange: models.TimeRange{To: &play.EventTS}, Limit: 10})
+		require.NoError(t, err)
+		require.Empty(t, page)
+	}},
+	{"DailyTotals", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		createUser(t, repo, 1, 0)
+		id := createPointling(t, repo, 1).PointlingID
+		addXP(t, repo, id, models.XPSourcePlay, 2)
+		addXP(t, repo, id, models.XPSourcePlay, 3)
+		event := addXP(t, repo, id, models.XPSourceDaily, 4)
+
+		totals, err := repo.GetDailyXPTotals(ctx, models.XPHistoryFilter{PointlingID: id})
+		require.NoError(t, err)
+		day := event.EventTS.UTC().Format(time.DateOnly)
+		require.Equal(t, []*models.XPDailyTotal{
+			{Day: day, Source: models.XPSourceDaily, TotalXP: 4, EventCount: 1},
+			{Day: day, Source: models.XPSourcePlay, TotalXP: 5, EventCount: 2},
+		}, totals)
+
+		totals, err = repo.GetDailyXPTotals(ctx, models.XPHistoryFilter{PointlingID: 404})
+		require.NoError(t, err)
+		require.Empty(t, totals)
+	}},
+}
+
+var xpConfigCases = []testCase{
+	{"NoneStored", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		cfg, err := repo.GetActiveXPConfig(ctx)
+		require.NoError(t, err)
+		require.Nil(t, cfg)
+
+		configs, err := repo.ListXPConfigVersions(ctx, 10)
+		require.NoError(t, err)
+		require.Empty(t, configs)
+	}},
+	{"VersionsNewestFirst", func(t *testing.T, repo repository.API) {
+		ctx := context.Background()
+		first := models.DefaultXPConfig()
+		require.NoError(t, repo.CreateXPConfigVersion(ctx, first))
+		second := models.DefaultXPConfig()
+		second.Curve.Base = 5
+		require.NoError(t, repo.CreateXPConfigVersion(ctx, second))
+		require.Greater(t, second.Version, first.Version)
+		require.False(t, second.CreatedAt.IsZero())
+
+		active, err := repo.GetActiveXPConfig(ctx)
+		require.NoError(t, err)
+		require.Equal(t, second.Version, active.Version)
+		require.Equal(t, second.Curve, active.Curve)
+		require.Equal(t, second.Sources, active.Sources)
+
+		configs, err := repo.ListXPConfigVersions(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, configs, 2)
+		require.Equal(t, second.Version, configs[0].Version)
+		require.Equal(t, first.Version, configs[1].Version)
+
+		configs, err = repo.ListXPConfigVersions(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, configs, 1)
+	}},
+}
+
+var personalityCases = []testCase{
+	{"CatalogAndPointlingLink", func(t *testing.T, repo repository.API) {
+		seeder, ok := repo.(PersonalitySeeder)
+		if !ok {
+			t.Skip("backend cannot seed personalities")
+		}
+		ctx := context.Background()
+		seeder.SeedPersonalities(
+			models.Personality{PersonalityID: 2, Code: "shy", Name: "Shy", Traits: []string{"quiet"}, IdleAnimationIDs: []string{}, DialogueTags: []string{}, Weight: 1},
+			models.Personality{PersonalityID: 1, Code: "bold", Name: "Bold", Traits: []string{}, IdleAnimationIDs: []string{"jump"}, DialogueTags: []string{}, Weight: 3,
+				XPBonusPercent: map[models.XPEventSource]int{models.XPSourcePlay: 10}},
+		)
+
+		personalities, err := repo.ListPersonalities(ctx)
+		require.NoError(t, err)
+		require.Len(t, personalities, 2)
+		require.Equal(t, "bold", personalities[0].Code)
+		require.Equal(t, "shy", personalities[1].Code)
+
+		bold, err := repo.GetPersonality(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, []string{"jump"}, bold.IdleAnimationIDs)
+		require.Equal(t, 10, bold.XPBonusPercent[models.XPSourcePlay])
+
+		missing, err := repo.GetPersonality(ctx, 404)
+		require.NoError(t, err)
+		require.Nil(t, missing)
+
+		createUser(t, repo, 1, 0)
+		pointling := models.NewPointling(1, nil)
+		pointling.PersonalityID = ptr(2)
+		require.NoError(t, repo.CreatePointling(ctx, pointling))
+		got, err := repo.GetPointlingByID(ctx, pointling.PointlingID)
+		require.NoError(t, err)
+		require.Equal(t, 2, *got.PersonalityID)
+
+		pointling = models.NewPointling(1, nil)
+		pointling.PersonalityID = ptr(404)
+		require.Error(t, repo.CreatePointling(ctx, pointling))
+	}},
+	{"EmptyCatalog", func(t *testing.T, repo repository.API) {
+		personalities, err := repo.ListPersonalities(context.Background())
+		require.NoError(t, err)
+		require.Empty(t, personalities)
+	}},
+}
+
+func eventIDs(events []*models.XPEvent) []int64 {
+	ids := make([]int64, len(events))
+	for i, e := range events {
+		ids[i] = e.EventID
+	}
+	return ids
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"my-pointlings-be/internal/models"
+)
+
+func (r *PointlingRepository) CreateLevelReward(ctx context.Context, reward *models.LevelReward) error {
+	query := `
+		INSERT INTO public.level_rewards (
+			pointling_id, level, options, expires_at
+		) VALUES ($1, $2, $3, $4)
+		RETURNING reward_id, offered_at`
+
+	options, err := json.Marshal(reward.Options)
+	if err != nil {
+		return fmt.Errorf("marshal reward options: %w", err)
+	}
+
+	err = r.txWrapper().QueryRowContext(ctx,
+		query,
+		reward.PointlingID,
+		reward.Level,
+		options,
+		reward.ExpiresAt,
+	).Scan(&reward.RewardID, &reward.OfferedAt)
+
+	if err != nil {
+		return fmt.Errorf("create level reward: %w", err)
+	}
+	return nil
+}
+
+func (r *PointlingRepository) GetLevelReward(ctx context.Context, rewardID int64) (*models.LevelReward, error) {
+	query := `
+		SELECT reward_id, pointling_id, level, options, chosen_option,
+			offered_at, expires_at, claimed_at
+		FROM public.level_rewards
+		WHERE reward_id = $1`
+
+	reward, err := scanLevelReward(r.txWrapper().QueryRowContext(ctx, query, rewardID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get level reward: %w", err)
+	}
+	return reward, nil
+}
+
+func (r *PointlingRepository) GetPendingRewards(ctx context.Context, pointlingID int64) ([]*models.LevelReward, error) {
+	query := `
+		SELECT reward_id, pointling_id, level, options, chosen_option,
+			offered_at, expires_at, claimed_at
+		FROM public.level_rewards
+		WHERE pointling_id = $1
+		AND claimed_at IS NULL
+		AND expires_at > now()
+		ORDER BY level ASC`
+
+	rows, err := r.txWrapper().QueryContext(ctx, query, pointlingID)
+	if err != nil {
+		return nil, fmt.Errorf("pending rewards query: %w", err)
+	}
+	defer rows.Close()
+
+	var rewards []*models.LevelReward
+	for rows.Next() {
+		reward, err := scanLevelReward(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan level reward: %w", err)
+		}
+		rewards = append(rewards, reward)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate level rewards: %w", err)
+	}
+	return rewards, nil
+}
+
+func (r *PointlingRepository) ClaimLevelReward(ctx context.Context, rewardID int64, option models.RewardOption) error {
+	// Only an open, unexpired offer can be claimed; a second choice matches no row.
+	query := `
+		UPDATE public.level_rewards
+		SET chosen_option = $2, claimed_at = now()
+		WHERE reward_id = $1
+		AND claimed_at IS NULL
+		AND expires_at > now()`
+
+	chosen, err := json.Marshal(option)
+	if err != nil {
+		return fmt.Errorf("marshal chosen option: %w", err)
+	}
+
+	result, err := r.txWrapper().ExecContext(ctx, query, rewardID, chosen)
+	if err != nil {
+		return fmt.Errorf("claim level reward: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return models.ErrRewardAlreadyClaimed
+	}
+	return nil
+}
+
+func (r *PointlingRepository) AddPointlingColor(ctx context.Context, pointlingID int64, colorHex string) error {
+	query := `
+		INSERT INTO public.pointling_colors (pointling_id, color_hex)
+		VALUES ($1, $2)
+		ON CONFLICT (pointling_id, color_hex) DO NOTHING`
+
+	result, err := r.txWrapper().ExecContext(ctx, query, pointlingID, colorHex)
+	if err != nil {
+		return fmt.Errorf("add color: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return models.ErrAlreadyOwned
+	}
+	return nil
+}
+
+func (r *PointlingRepository) GetPointlingColors(ctx context.Context, pointlingID int64) ([]*models.PointlingColor, error) {
+	query := `
+		SELECT pointling_id, color_hex, acquired_at
+		FROM public.pointling_colors
+		WHERE pointling_id = $1
+		ORDER BY acquired_at ASC`
+
+	rows, err := r.txWrapper().QueryContext(ctx, query, pointlingID)
+	if err != nil {
+		return nil, fmt.Errorf("get colors query: %w", err)
+	}
+	defer rows.Close()
+
+	var colors []*models.PointlingColor
+	for rows.Next() {
+		color := &models.PointlingColor{}
+		if err := rows.Scan(&color.PointlingID, &color.ColorHex, &color.AcquiredAt); err != nil {
+			return nil, fmt.Errorf("scan pointling color: %w", err)
+		}
+		colors = append(colors, color)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate colors: %w", err)
+	}
+	return colors, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanLevelReward(row rowScanner) (*models.LevelReward, error) {
+	reward := &models.LevelReward{}
+	var options, chosen []byte
+
+	err := row.Scan(
+		&reward.RewardID,
+		&reward.PointlingID,
+		&reward.Level,
+		&options,
+		&chosen,
+		&reward.OfferedAt,
+		&reward.ExpiresAt,
+		&reward.ClaimedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(options, &reward.Options); err != nil {
+		return nil, fmt.Errorf("unmarshal options: %w", err)
+	}
+	if chosen != nil {
+		reward.ChosenOption = &models.RewardOption{}
+		if err := json.Unmarshal(chosen, reward.ChosenOption); err != nil {
+			return nil, fmt.Errorf("unmarshal chosen option: %w", err)
+		}
+	}
+	return reward, nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"my-pointlings-be/internal/models"
+)
+
+// xpConfigDocument is the jsonb payload stored for each config version.
+type xpConfigDocument struct {
+	Curve   models.LevelCurve                            `json:"curve"`
+	Sources map[models.XPEventSource]models.XPSourceRule `json:"sources"`
+}
+
+func (r *PointlingRepository) GetActiveXPConfig(ctx context.Context) (*models.XPConfig, error) {
+	query := `
+		SELECT version, config, created_at
+		FROM public.xp_config_versions
+		ORDER BY version DESC
+		LIMIT 1`
+
+	cfg, err := scanXPConfig(r.txWrapper().QueryRowContext(ctx, query))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get active xp config: %w", err)
+	}
+	return cfg, nil
+}
+
+func (r *PointlingRepository) ListXPConfigVersions(ctx context.Context, limit int) ([]*models.XPConfig, error) {
+	query := `
+		SELECT version, config, created_at
+		FROM public.xp_config_versions
+		ORDER BY version DESC
+		LIMIT $1`
+
+	rows, err := r.txWrapper().QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list xp config versions: %w", err)
+	}
+	defer rows.Close()
+
+	var configs []*models.XPConfig
+	for rows.Next() {
+		cfg, err := scanXPConfig(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan xp config: %w", err)
+		}
+		configs = append(configs, cfg)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate xp configs: %w", err)
+	}
+	return configs, nil
+}
+
+func (r *PointlingRepository) CreateXPConfigVersion(ctx context.Context, cfg *models.XPConfig) error {
+	query := `
+		INSERT INTO public.xp_config_versions (config)
+		VALUES ($1)
+		RETURNING version, created_at`
+
+	doc, err := json.Marshal(xpConfigDocument{Curve: cfg.Curve, Sources: cfg.Sources})
+	if err != nil {
+		return fmt.Errorf("marshal xp config: %w", err)
+	}
+
+	err = r.txWrapper().QueryRowContext(ctx, query, doc).Scan(&cfg.Version, &cfg.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("create xp config version: %w", err)
+	}
+	return nil
+}
+
+func scanXPConfig(row rowScanner) (*models.XPConfig, error) {
+	cfg := &models.XPConfig{}
+	var raw []byte
+
+	if err := row.Scan(&cfg.Version, &raw, &cfg.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	var doc xpConfigDocument
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("unmarshal xp config: %w", err)
+	}
+	cfg.Curve = doc.Curve
+	cfg.Sources = doc.Sources
+	return cfg, nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"my-pointlings-be/internal/auth"
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository"
+)
+
+func (s *PointlingService) ListAuditLog(c context.Context, req models.AuditLogRequest) (models.AuditLogResponse, error) {
+	filter := models.AuditLogFilter{
+		Limit:      req.Limit,
+		TargetType: req.TargetType,
+		TargetID:   req.TargetID,
+	}
+	switch {
+	case filter.Limit <= 0:
+		filter.Limit = models.DefaultAuditLogLimit
+	case filter.Limit > models.MaxAuditLogLimit:
+		filter.Limit = models.MaxAuditLogLimit
+	}
+	if req.Before != "" {
+		id, err := strconv.ParseInt(req.Before, 10, 64)
+		if err != nil || id <= 0 {
+			return models.AuditLogResponse{}, models.NewValidationError(fmt.Errorf("malformed before: %q", req.Before))
+		}
+		filter.BeforeID = id
+	}
+
+	// Fetch one extra row to learn whether another page follows.
+	pageSize := filter.Limit
+	filter.Limit++
+	entries, err := s.PointlingRepo.ListAuditEntries(c, filter)
+	if err != nil {
+		return models.AuditLogResponse{}, err
+	}
+	res := models.AuditLogResponse{Entries: []models.AuditEntry{}}
+	if len(entries) > pageSize {
+		entries = entries[:pageSize]
+		res.NextBefore = strconv.FormatInt(entries[pageSize-1].AuditID, 10)
+	}
+	for _, e := range entries {
+		res.Entries = append(res.Entries, *e)
+	}
+	return res, nil
+}
+
+// audit records an admin change made by the caller in c. Pass the
+// transaction's repository so the record commits with the change.
+func audit(c context.Context, repo repository.API, action, targetType, targetID string, before, after interface{}) error {
+	entry, err := models.NewAuditEntry(auditActor(c), action, targetType, targetID, before, after)
+	if err != nil {
+		return err
+	}
+	return repo.CreateAuditEntry(c, entry)
+}
+
+// auditActor names the authenticated caller, or "system" for work that did
+// not come in through a request.
+func auditActor(c context.Context) string {
+	if principal, ok := auth.FromContext(c); ok {
+		return principal.Actor()
+	}
+	return "system"
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository"
+)
+
+func (s *PointlingService) ListColors(c context.Context, pointlingID string) (models.ColorListResponse, error) {
+	id, err := parseID("pointling_id", pointlingID)
+	if err != nil {
+		return models.ColorListResponse{}, err
+	}
+	p, err := s.PointlingRepo.GetPointlingByID(c, id)
+	if err != nil {
+		return models.ColorListResponse{}, err
+	}
+	if p == nil {
+		return models.ColorListResponse{}, fmt.Errorf("%w: %d", models.ErrPointlingNotFound, id)
+	}
+	colors, err := s.PointlingRepo.GetPointlingColors(c, id)
+	if err != nil {
+		return models.ColorListResponse{}, err
+	}
+
+	res := models.ColorListResponse{Colors: []models.PointlingColor{}}
+	res.ActiveColor = p.Look.Color
+	for _, pc := range colors {
+		res.Colors = append(res.Colors, *pc)
+	}
+	return res, nil
+}
+
+func (s *PointlingService) SetColor(c context.Context, req models.SetColorRequest) (models.Pointling, error) {
+	id, err := parseID("pointling_id", req.PointlingID)
+	if err != nil {
+		return models.Pointling{}, err
+	}
+	hex := strings.ToUpper(strings.TrimSpace(req.ColorHex))
+
+	var updated models.Pointling
+	err = s.PointlingRepo.InTransaction(c, func(tx repository.API) error {
+		p, err := tx.GetPointlingByID(c, id)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			return fmt.Errorf("%w: %d", models.ErrPointlingNotFound, id)
+		}
+
+		colors, err := tx.GetPointlingColors(c, id)
+		if err != nil {
+			return err
+		}
+		owned := false
+		for _, pc := range colors {
+			if pc.ColorHex == hex {
+				owned = true
+				break
+			}
+		}
+		if !owned {
+			return models.ErrColorNotOwned
+		}
+
+		p.Look.Color = hex
+		if err := tx.UpdatePointlingLook(c, id, p.Look); err != nil {
+			return err
+		}
+		updated = *p
+		return nil
+	})
+	if err != nil {
+		return models.Pointling{}, fmt.Errorf("set color: %w", err)
+	}
+	return updated, nil
+}
+
+// grantMilestoneColor gives the pointling the next palette color it does not
+// own. It returns "" when the palette is already complete.
+func grantMilestoneColor(c context.Context, repo repository.API, pointlingID int64) (string, error) {
+	owned, err := repo.GetPointlingColors(c, pointlingID)
+	if err != nil {
+		return "", err
+	}
+	hex := models.NextPaletteColor(owned)
+	if hex == "" {
+		return "", nil
+	}
+	if err := repo.AddPointlingColor(c, pointlingID, hex); err != nil {
+		return "", err
+	}
+	return hex, nil
+}
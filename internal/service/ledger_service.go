@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository"
+)
+
+func (s *PointlingService) GetLedger(c context.Context, req models.LedgerRequest) (models.LedgerResponse, error) {
+	limit := req.Limit
+	switch {
+	case limit <= 0:
+		limit = models.DefaultLedgerLimit
+	case limit > models.MaxLedgerLimit:
+		limit = models.MaxLedgerLimit
+	}
+	var beforeID int64
+	if req.Before != "" {
+		id, err := strconv.ParseInt(req.Before, 10, 64)
+		if err != nil || id <= 0 {
+			return models.LedgerResponse{}, fmt.Errorf("%w: malformed before", models.ErrInvalidLedgerEntry)
+		}
+		beforeID = id
+	}
+
+	// Fetch one extra row to learn whether another page follows.
+	userID, err := parseID("user_id", req.UserID)
+	if err != nil {
+		return models.LedgerResponse{}, err
+	}
+	entries, err := s.PointlingRepo.GetLedgerEntries(c, userID, beforeID, limit+1)
+	if err != nil {
+		return models.LedgerResponse{}, err
+	}
+	res := models.LedgerResponse{Entries: []models.LedgerEntry{}}
+	if len(entries) > limit {
+		entries = entries[:limit]
+		res.NextBefore = strconv.FormatInt(entries[limit-1].EntryID, 10)
+	}
+	for _, e := range entries {
+		res.Entries = append(res.Entries, *e)
+	}
+	return res, nil
+}
+
+// ReconcilePoints compares a user's stored balance with the sum of their
+// ledger entries. Any difference means the balance was changed off-ledger.
+func (s *PointlingService) ReconcilePoints(c context.Context, userID string) (models.LedgerReconciliation, error) {
+	id, err := parseID("user_id", userID)
+	if err != nil {
+		return models.LedgerReconciliation{}, err
+	}
+	user, err := s.PointlingRepo.GetUser(c, id)
+	if err != nil {
+		return models.LedgerReconciliation{}, err
+	}
+	if user == nil {
+		return models.LedgerReconciliation{}, fmt.Errorf("%w: %d", models.ErrUserNotFound, id)
+	}
+	ledgerBalance, err := s.PointlingRepo.GetLedgerBalance(c, id)
+	if err != nil {
+		return models.LedgerReconciliation{}, err
+	}
+	return models.LedgerReconciliation{
+		UserID:        id,
+		StoredBalance: user.PointBalance,
+		LedgerBalance: ledgerBalance,
+		Difference:    user.PointBalance - ledgerBalance,
+		Consistent:    user.PointBalance == ledgerBalance,
+	}, nil
+}
+
+// BackfillLedger gives users created before the ledger existed an opening
+// entry for their balance. It is safe to re-run.
+func (s *PointlingService) BackfillLedger(c context.Context) (models.LedgerBackfillResponse, error) {
+	var res models.LedgerBackfillResponse
+	err := s.PointlingRepo.InTransaction(c, func(tx repository.API) error {
+		n, err := tx.BackfillOpeningBalances(c)
+		if err != nil {
+			return err
+		}
+		res.UsersBackfilled = n
+		return audit(c, tx, models.AuditActionLedgerBackfill, "user", "*", nil, res)
+	})
+	if err != nil {
+		return models.LedgerBackfillResponse{}, err
+	}
+	return res, nil
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"testing"
+
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository/memory"
+
+	"github.com/stretchr/testify/require"
+)
+
+// requireLedgerMatchesBalance replays a user's ledger oldest first and checks
+// every running balance, ending on the balance stored on the user.
+func requireLedgerMatchesBalance(t *testing.T, svc *PointlingService, userID int64) {
+	t.Helper()
+	ctx := context.Background()
+	entries, err := svc.PointlingRepo.GetLedgerEntries(ctx, userID, 0, 100)
+	require.NoError(t, err)
+	slices.Reverse(entries)
+
+	var running int64
+	for _, e := range entries {
+		running += e.Delta
+		require.Equal(t, running, e.BalanceAfter, "entry %d", e.EntryID)
+	}
+	user, err := svc.PointlingRepo.GetUser(ctx, userID)
+	require.NoError(t, err)
+	require.Equal(t, user.PointBalance, running)
+
+	rec, err := svc.ReconcilePoints(ctx, strconv.FormatInt(userID, 10))
+	require.NoError(t, err)
+	require.True(t, rec.Consistent, "%+v", rec)
+}
+
+func TestBackfillLedger(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.New()
+	svc := New(repo)
+	// Users 1 and 2 predate the ledger; user 3 only ever moved points through it.
+	require.NoError(t, repo.CreateUser(ctx, &models.User{UserID: 1, DisplayName: "one", PointBalance: 100}))
+	require.NoError(t, repo.CreateUser(ctx, &models.User{UserID: 2, DisplayName: "two"}))
+	require.NoError(t, repo.CreateUser(ctx, &models.User{UserID: 3, DisplayName: "three"}))
+	_, err := svc.UpdateUserPoints(ctx, models.UpdateUserPointsRequest{UserID: "3", PointAmount: 40, Reason: models.LedgerReasonAdminGrant})
+	require.NoError(t, err)
+
+	res, err := svc.BackfillLedger(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, res.UsersBackfilled)
+	entries, err := repo.GetLedgerEntries(ctx, 1, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, models.LedgerReasonOpeningBalance, entries[0].Reason)
+	require.EqualValues(t, 100, entries[0].Delta)
+
+	// A second run finds nothing left to backfill and posts nothing.
+	res, err = svc.BackfillLedger(ctx)
+	require.NoError(t, err)
+	require.Zero(t, res.UsersBackfilled)
+	for id, want := range map[int64]int{1: 1, 2: 0, 3: 1} {
+		entries, err := repo.GetLedgerEntries(ctx, id, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, entries, want, "user %d", id)
+		requireLedgerMatchesBalance(t, svc, id)
+	}
+
+	// Later movements keep the running balances in step.
+	_, err = svc.UpdateUserPoints(ctx, models.UpdateUserPointsRequest{UserID: "1", PointAmount: -30, Reason: models.LedgerReasonAdminAdjustment})
+	require.NoError(t, err)
+	_, err = svc.UpdateUserPoints(ctx, models.UpdateUserPointsRequest{UserID: "1", PointAmount: 5, Reason: models.LedgerReasonPromo})
+	require.NoError(t, err)
+	requireLedgerMatchesBalance(t, svc, 1)
+
+	res, err = svc.BackfillLedger(ctx)
+	require.NoError(t, err)
+	require.Zero(t, res.UsersBackfilled)
+	requireLedgerMatchesBalance(t, svc, 1)
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository"
+)
+
+// refreshLook rebuilds a pointling's look from its inventory and stores it.
+func refreshLook(c context.Context, repo repository.API, pointlingID int64) (*models.Pointling, error) {
+	p, err := repo.GetPointlingByID(c, pointlingID)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, fmt.Errorf("%w: %d", models.ErrPointlingNotFound, pointlingID)
+	}
+	owned, err := repo.GetItems(c, pointlingID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	p.Look = models.BuildLook(p.Look, owned)
+	if err := repo.UpdatePointlingLook(c, pointlingID, p.Look); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (s *PointlingService) GetRenderManifest(c context.Context, pointlingID string) (models.RenderManifest, error) {
+	id, err := parseID("pointling_id", pointlingID)
+	if err != nil {
+		return models.RenderManifest{}, err
+	}
+	p, err := s.PointlingRepo.GetPointlingByID(c, id)
+	if err != nil {
+		return models.RenderManifest{}, err
+	}
+	if p == nil {
+		return models.RenderManifest{}, fmt.Errorf("%w: %d", models.ErrPointlingNotFound, id)
+	}
+	owned, err := s.PointlingRepo.GetItems(c, id, nil)
+	if err != nil {
+		return models.RenderManifest{}, err
+	}
+	return models.BuildRenderManifest(p, owned), nil
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"my-pointlings-be/internal/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderManifestETag(t *testing.T) {
+	ctx := context.Background()
+	f := newPurchaseFixture(t, 100, nil)
+	id := strconv.FormatInt(f.pointling.PointlingID, 10)
+	manifest := func() models.RenderManifest {
+		t.Helper()
+		m, err := f.svc.GetRenderManifest(ctx, id)
+		require.NoError(t, err)
+		require.NotEmpty(t, m.ETag)
+		return m
+	}
+	toggle := func(equipped bool) {
+		t.Helper()
+		_, err := f.svc.ToggleEquipped(ctx, models.ToggleEquippedRequest{
+			PointlingID: id,
+			ItemID:      strconv.FormatInt(f.hat.ItemID, 10),
+			Equipped:    equipped,
+		})
+		require.NoError(t, err)
+	}
+
+	bare := manifest()
+	require.Equal(t, bare.ETag, manifest().ETag, "an unchanged look keeps its tag")
+
+	// Owning the hat draws nothing until it is worn.
+	_, err := f.buy(f.hat.ItemID)
+	require.NoError(t, err)
+	require.Equal(t, bare.ETag, manifest().ETag)
+
+	toggle(true)
+	worn := manifest()
+	require.NotEqual(t, bare.ETag, worn.ETag)
+	require.Len(t, worn.Layers, 2)
+
+	toggle(false)
+	require.Equal(t, bare.ETag, manifest().ETag, "the tag follows the look, not its history")
+}
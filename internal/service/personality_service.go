@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"math/rand/v2"
+
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository"
+)
+
+func (s *PointlingService) ListPersonalities(c context.Context) (models.PersonalityListResponse, error) {
+	catalog, err := s.PointlingRepo.ListPersonalities(c)
+	if err != nil {
+		return models.PersonalityListResponse{}, err
+	}
+	res := models.PersonalityListResponse{Personalities: []models.Personality{}}
+	for _, p := range catalog {
+		res.Personalities = append(res.Personalities, *p)
+	}
+	return res, nil
+}
+
+// choosePersonality returns the requested personality, or a weighted random
+// pick from the catalog when none was requested. An empty catalog yields nil.
+func choosePersonality(c context.Context, repo repository.API, requested *int) (*models.Personality, error) {
+	if requested != nil {
+		p, err := repo.GetPersonality(c, *requested)
+		if err != nil {
+			return nil, err
+		}
+		if p == nil {
+			return nil, models.ErrPersonalityNotFound
+		}
+		return p, nil
+	}
+
+	catalog, err := repo.ListPersonalities(c)
+	if err != nil {
+		return nil, err
+	}
+	return models.PickWeightedPersonality(catalog, rand.IntN), nil
+}
+
+// pointlingPersonality loads the pointling's personality, or nil if it has none.
+func pointlingPersonality(c context.Context, repo repository.API, p *models.Pointling) (*models.Personality, error) {
+	if p.PersonalityID == nil {
+		return nil, nil
+	}
+	return repo.GetPersonality(c, *p.PersonalityID)
+}
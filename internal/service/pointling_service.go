@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"my-pointlings-be/internal/models"
 	"my-pointlings-be/internal/repository"
@@ -11,115 +12,348 @@ import (
 
 type PointlingService struct {
 	PointlingRepo repository.API
+	// Clock tells the service the time; daily XP windows are computed from it.
+	Clock func() time.Time
 }
 
 type API interface {
 	ListUsers(c context.Context) (models.UserListResponse, error)
-	CreateUser(c context.Context, user models.CreateUserRequest) (models.SuccessResponse, error)
+	CreateUser(c context.Context, user models.CreateUserRequest) (models.User, error)
 	GetUser(c context.Context, userID string) (models.User, error)
-	UpdateUserPoints(c context.Context, update models.UpdateUserPointsRequest) (models.SuccessResponse, error)
-	CreatePointling(c context.Context, req models.CreatePointlingRequest) (models.SuccessResponse, error)
-	GetPointling(c context.Context, pointlingID string) (models.Pointling, error)
+	UpdateUserPoints(c context.Context, update models.UpdateUserPointsRequest) (models.User, error)
+	UpdateUserTimezone(c context.Context, update models.UpdateUserTimezoneRequest) (models.User, error)
+	CreatePointling(c context.Context, req models.CreatePointlingRequest) (models.Pointling, error)
+	GetPointling(c context.Context, pointlingID string, expand []string) (models.Pointling, error)
 	AddXP(c context.Context, req models.AddXPRequest) (models.XPUpdateResponse, error)
-	UpdateNickname(c context.Context, req models.UpdateNicknameRequest) (models.SuccessResponse, error)
+	UpdateNickname(c context.Context, req models.UpdateNicknameRequest) (models.Pointling, error)
 	ListUserPointlings(c context.Context, userID string) (models.PointlingListResponse, error)
 	ListItems(c context.Context) (models.ItemListResponse, error)
 	GetItem(c context.Context, itemID string) (models.Item, error)
-	CreateItem(c context.Context, item models.CreateItemRequest) (models.SuccessResponse, error)
+	CreateItem(c context.Context, item models.CreateItemRequest) (models.Item, error)
 	GetInventory(c context.Context, pointlingID string) (models.InventoryResponse, error)
-	AcquireItem(c context.Context, acquire models.AcquireItemRequest) (models.SuccessResponse, error)
 	ToggleEquipped(c context.Context, toggle models.ToggleEquippedRequest) (models.Pointling, error)
-	SpendPoints(c context.Context, spend models.SpendPointsRequest) (models.SuccessResponse, error)
+	GetPendingRewards(c context.Context, pointlingID string) (models.PendingRewardsResponse, error)
+	ClaimReward(c context.Context, req models.ClaimRewardRequest) (models.LevelReward, error)
+	ListColors(c context.Context, pointlingID string) (models.ColorListResponse, error)
+	SetColor(c context.Context, req models.SetColorRequest) (models.Pointling, error)
+	GetXPHistory(c context.Context, req models.XPHistoryRequest) (models.XPHistoryResponse, error)
+	GetXPLimits(c context.Context, pointlingID string) (models.XPLimitsResponse, error)
+	GetXPConfig(c context.Context) (models.XPConfig, error)
+	ListXPConfigVersions(c context.Context) (models.XPConfigListResponse, error)
+	UpdateXPConfig(c context.Context, req models.UpdateXPConfigRequest) (models.XPConfig, error)
+	ListPersonalities(c context.Context) (models.PersonalityListResponse, error)
+	GetRenderManifest(c context.Context, pointlingID string) (models.RenderManifest, error)
+	PurchaseItem(c context.Context, req models.PurchaseItemRequest) (models.TransactionSuccess, error)
+	GetLedger(c context.Context, req models.LedgerRequest) (models.LedgerResponse, error)
+	ReconcilePoints(c context.Context, userID string) (models.LedgerReconciliation, error)
+	BackfillLedger(c context.Context) (models.LedgerBackfillResponse, error)
+	GetSpendHistory(c context.Context, req models.SpendHistoryRequest) (models.SpendHistoryResponse, error)
+	ExportSpendHistory(c context.Context, req models.SpendHistoryRequest) ([]models.PointSpend, error)
+	RefundSpend(c context.Context, req models.RefundSpendRequest) (models.SpendReversal, error)
+	GetPointlingOwnerID(c context.Context, pointlingID string) (int64, error)
+	ListAuditLog(c context.Context, req models.AuditLogRequest) (models.AuditLogResponse, error)
 }
 
 func New(pointlingRepo repository.API) *PointlingService {
-	return &PointlingService{PointlingRepo: pointlingRepo}
+	return &PointlingService{PointlingRepo: pointlingRepo, Clock: time.Now}
 }
 
 func (s *PointlingService) ListUsers(c context.Context) (models.UserListResponse, error) {
-	users, err := s.PointlingRepo.ListUsers(100, 0)
+	users, err := s.PointlingRepo.ListUsers(c, 100, 0)
 	if err != nil {
 		return models.UserListResponse{}, fmt.Errorf("list users: %w", err)
 	}
-	var result models.UserListResponse
+	result := models.UserListResponse{Users: []models.User{}}
 	for _, u := range users {
-		result.Users = append(result.Users, models.User{
-			UserID:       u.UserID,
-			DisplayName:  u.DisplayName,
-			PointBalance: u.PointBalance,
-			CreatedAt:    u.CreatedAt,
-		})
+		result.Users = append(result.Users, *u)
 	}
 	return result, nil
 }
 
-func (s *PointlingService) CreateUser(c context.Context, req models.CreateUserRequest) (models.SuccessResponse, error) {
-	userID, _ := strconv.ParseInt(req.UserID, 10, 64)
+func (s *PointlingService) CreateUser(c context.Context, req models.CreateUserRequest) (models.User, error) {
+	userID, err := parseID("user_id", req.UserID)
+	if err != nil {
+		return models.User{}, err
+	}
+	if req.Timezone != "" {
+		if err := validateTimezone(req.Timezone); err != nil {
+			return models.User{}, err
+		}
+	}
 	user := &models.User{
-		UserID:       userID,
-		DisplayName:  req.DisplayName,
-		PointBalance: int64(req.PointBalance),
+		UserID:      userID,
+		DisplayName: req.DisplayName,
+		Timezone:    req.Timezone,
 	}
-	if err := s.PointlingRepo.CreateUser(user); err != nil {
-		return models.SuccessResponse{Success: false}, fmt.Errorf("create user: %w", err)
+	if err := s.PointlingRepo.CreateUser(c, user); err != nil {
+		return models.User{}, fmt.Errorf("create user: %w", err)
 	}
-	return models.SuccessResponse{Success: true, Message: "User created successfully"}, nil
+	// Read back the row for its defaults and creation time.
+	return s.GetUser(c, req.UserID)
 }
 
 func (s *PointlingService) GetUser(c context.Context, userID string) (models.User, error) {
-	id, _ := strconv.ParseInt(userID, 10, 64)
-	user, err := s.PointlingRepo.GetUser(id)
+	id, err := parseID("user_id", userID)
 	if err != nil {
 		return models.User{}, err
 	}
+	user, err := s.PointlingRepo.GetUser(c, id)
+	if err != nil {
+		return models.User{}, err
+	}
+	if user == nil {
+		return models.User{}, fmt.Errorf("%w: %d", models.ErrUserNotFound, id)
+	}
 	return *user, nil
 }
 
-func (s *PointlingService) UpdateUserPoints(c context.Context, req models.UpdateUserPointsRequest) (models.SuccessResponse, error) {
-	id, _ := strconv.ParseInt(req.UserID, 10, 64)
-	if err := s.PointlingRepo.UpdatePointBalance(id, int64(req.PointAmount)); err != nil {
-		return models.SuccessResponse{Success: false}, err
+func (s *PointlingService) UpdateUserPoints(c context.Context, req models.UpdateUserPointsRequest) (models.User, error) {
+	id, err := parseID("user_id", req.UserID)
+	if err != nil {
+		return models.User{}, err
+	}
+	// Spends and opening balances are only posted by their own flows.
+	if req.Reason == models.LedgerReasonSpend || req.Reason == models.LedgerReasonOpeningBalance {
+		return models.User{}, fmt.Errorf("%w: %s cannot be posted directly", models.ErrInvalidLedgerEntry, req.Reason)
+	}
+	entry := &models.LedgerEntry{
+		UserID:    id,
+		Delta:     int64(req.PointAmount),
+		Reason:    req.Reason,
+		Reference: req.Reference,
+	}
+	var after models.User
+	err = s.PointlingRepo.InTransaction(c, func(tx repository.API) error {
+		before, err := tx.GetUser(c, id)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return fmt.Errorf("%w: %d", models.ErrUserNotFound, id)
+		}
+		if err := tx.PostLedgerEntry(c, entry); err != nil {
+			return err
+		}
+		after = *before
+		after.PointBalance = entry.BalanceAfter
+		return audit(c, tx, models.AuditActionPointsAdjust, "user", strconv.FormatInt(id, 10), before, after)
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+	return after, nil
+}
+
+func (s *PointlingService) UpdateUserTimezone(c context.Context, req models.UpdateUserTimezoneRequest) (models.User, error) {
+	id, err := parseID("user_id", req.UserID)
+	if err != nil {
+		return models.User{}, err
+	}
+	if err := validateTimezone(req.Timezone); err != nil {
+		return models.User{}, err
+	}
+	if err := s.PointlingRepo.UpdateUserTimezone(c, id, req.Timezone); err != nil {
+		return models.User{}, err
 	}
-	return models.SuccessResponse{Success: true}, nil
+	return s.GetUser(c, req.UserID)
 }
 
-func (s *PointlingService) CreatePointling(c context.Context, req models.CreatePointlingRequest) (models.SuccessResponse, error) {
-	userID, _ := strconv.ParseInt(req.UserID, 10, 64)
+func (s *PointlingService) CreatePointling(c context.Context, req models.CreatePointlingRequest) (models.Pointling, error) {
+	userID, err := parseID("user_id", req.UserID)
+	if err != nil {
+		return models.Pointling{}, err
+	}
 	pointling := models.NewPointling(userID, &req.Name)
-	if err := s.PointlingRepo.CreatePointling(pointling); err != nil {
-		return models.SuccessResponse{Success: false}, err
+	err = s.PointlingRepo.InTransaction(c, func(tx repository.API) error {
+		cfg, err := activeXPConfig(c, tx)
+		if err != nil {
+			return err
+		}
+		pointling.RequiredXP = cfg.Curve.RequiredXP(pointling.Level)
+
+		personality, err := choosePersonality(c, tx, req.PersonalityID)
+		if err != nil {
+			return err
+		}
+		if personality != nil {
+			pointling.PersonalityID = &personality.PersonalityID
+		}
+
+		if err := tx.CreatePointling(c, pointling); err != nil {
+			return err
+		}
+		return tx.AddPointlingColor(c, pointling.PointlingID, pointling.Look.Color)
+	})
+	if err != nil {
+		return models.Pointling{}, err
 	}
-	return models.SuccessResponse{Success: true}, nil
+	return *pointling, nil
 }
 
-func (s *PointlingService) GetPointling(c context.Context, pointlingID string) (models.Pointling, error) {
-	id := parseID(pointlingID)
-	p, err := s.PointlingRepo.GetPointlingByID(id)
+func (s *PointlingService) GetPointling(c context.Context, pointlingID string, expand []string) (models.Pointling, error) {
+	expandPersonality := false
+	for _, e := range expand {
+		switch e {
+		case models.ExpandPersonality:
+			expandPersonality = true
+		default:
+			return models.Pointling{}, fmt.Errorf("%w: %q", models.ErrInvalidExpand, e)
+		}
+	}
+
+	id, err := parseID("pointling_id", pointlingID)
 	if err != nil {
 		return models.Pointling{}, err
 	}
+	p, err := s.PointlingRepo.GetPointlingByID(c, id)
+	if err != nil {
+		return models.Pointling{}, err
+	}
+	if p == nil {
+		return models.Pointling{}, fmt.Errorf("%w: %d", models.ErrPointlingNotFound, id)
+	}
+	if expandPersonality && p.PersonalityID != nil {
+		if p.Personality, err = s.PointlingRepo.GetPersonality(c, *p.PersonalityID); err != nil {
+			return models.Pointling{}, err
+		}
+	}
 	return *p, nil
 }
 
+// GetPointlingOwnerID returns the ID of the user that owns a pointling.
+func (s *PointlingService) GetPointlingOwnerID(c context.Context, pointlingID string) (int64, error) {
+	id, err := parseID("pointling_id", pointlingID)
+	if err != nil {
+		return 0, err
+	}
+	p, err := s.PointlingRepo.GetPointlingByID(c, id)
+	if err != nil {
+		return 0, err
+	}
+	if p == nil {
+		return 0, fmt.Errorf("%w: %d", models.ErrPointlingNotFound, id)
+	}
+	return p.UserID, nil
+}
+
 func (s *PointlingService) AddXP(c context.Context, req models.AddXPRequest) (models.XPUpdateResponse, error) {
-	return models.XPUpdateResponse{
-		LeveledUp:  true,
-		NewLevel:   2,
-		RequiredXP: 10,
-	}, nil
+	id, err := parseID("pointling_id", req.PointlingID)
+	if err != nil {
+		return models.XPUpdateResponse{}, err
+	}
+
+	var res models.XPUpdateResponse
+	err = s.PointlingRepo.InTransaction(c, func(tx repository.API) error {
+		cfg, err := activeXPConfig(c, tx)
+		if err != nil {
+			return err
+		}
+		rule, ok := cfg.Rule(req.Source)
+		if !ok {
+			return models.ErrInvalidXPSource
+		}
+
+		// Hold the pointling's row lock for the rest of the transaction so
+		// concurrent gains apply one after another against fresh XP values.
+		p, err := tx.LockPointling(c, id)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			return fmt.Errorf("%w: %d", models.ErrPointlingNotFound, id)
+		}
+
+		personality, err := pointlingPersonality(c, tx, p)
+		if err != nil {
+			return err
+		}
+		rule = personality.BoostRule(req.Source, rule)
+		// Gains always come from the XP config, never from the client.
+		gain := rule.PerAction
+
+		user, err := tx.GetUser(c, p.UserID)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return fmt.Errorf("%w: %d", models.ErrUserNotFound, p.UserID)
+		}
+
+		event := &models.XPEvent{
+			PointlingID: id,
+			Source:      req.Source,
+			XPAmount:    gain,
+		}
+		limit := models.XPDailyCap{
+			Window: models.DailyWindow(s.Clock(), user.Location()),
+			Max:    rule.MaxDaily,
+		}
+		if err := tx.AddXP(c, event, limit); err != nil {
+			return err
+		}
+
+		// AddXP trims a gain that reaches the cap to what was left of it.
+		levels := p.ApplyXP(event.XPAmount, cfg.Curve)
+		if err := tx.UpdatePointlingXP(c, id, p.CurrentXP, p.RequiredXP); err != nil {
+			return err
+		}
+		if len(levels) > 0 {
+			if err := tx.UpdatePointlingLevel(c, id, p.Level); err != nil {
+				return err
+			}
+		}
+
+		res = models.XPUpdateResponse{
+			XPGained:      event.XPAmount,
+			LeveledUp:     len(levels) > 0,
+			NewLevel:      p.Level,
+			CurrentXP:     p.CurrentXP,
+			RequiredXP:    p.RequiredXP,
+			LevelsCrossed: levels,
+		}
+		for _, level := range levels {
+			if models.IsColorMilestone(level) {
+				hex, err := grantMilestoneColor(c, tx, id)
+				if err != nil {
+					return fmt.Errorf("grant level %d color: %w", level, err)
+				}
+				if hex != "" {
+					res.ColorsUnlocked = append(res.ColorsUnlocked, hex)
+				}
+			}
+			reward, err := offerLevelReward(c, tx, id, level)
+			if err != nil {
+				return fmt.Errorf("offer level %d reward: %w", level, err)
+			}
+			if reward != nil {
+				res.Rewards = append(res.Rewards, reward.ToOptionsResponse())
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return models.XPUpdateResponse{}, fmt.Errorf("add xp: %w", err)
+	}
+	return res, nil
 }
 
-func (s *PointlingService) UpdateNickname(c context.Context, req models.UpdateNicknameRequest) (models.SuccessResponse, error) {
-	id := parseID(req.PointlingID)
-	if err := s.PointlingRepo.UpdatePointlingNickname(id, &req.Nickname); err != nil {
-		return models.SuccessResponse{Success: false}, err
+func (s *PointlingService) UpdateNickname(c context.Context, req models.UpdateNicknameRequest) (models.Pointling, error) {
+	id, err := parseID("pointling_id", req.PointlingID)
+	if err != nil {
+		return models.Pointling{}, err
+	}
+	if err := s.PointlingRepo.UpdatePointlingNickname(c, id, &req.Nickname); err != nil {
+		return models.Pointling{}, err
 	}
-	return models.SuccessResponse{Success: true}, nil
+	return s.GetPointling(c, req.PointlingID, nil)
 }
 
 func (s *PointlingService) ListUserPointlings(c context.Context, userID string) (models.PointlingListResponse, error) {
-	id := parseID(userID)
-	plist, err := s.PointlingRepo.GetPointlingByUserID(id)
+	id, err := parseID("user_id", userID)
+	if err != nil {
+		return models.PointlingListResponse{}, err
+	}
+	plist, err := s.PointlingRepo.GetPointlingByUserID(c, id)
 	if err != nil {
 		return models.PointlingListResponse{}, err
 	}
@@ -127,11 +361,11 @@ func (s *PointlingService) ListUserPointlings(c context.Context, userID string)
 }
 
 func (s *PointlingService) ListItems(c context.Context) (models.ItemListResponse, error) {
-	items, err := s.PointlingRepo.ListItems(nil, nil, nil)
+	items, err := s.PointlingRepo.ListItems(c, nil, nil, nil)
 	if err != nil {
 		return models.ItemListResponse{}, err
 	}
-	var res models.ItemListResponse
+	res := models.ItemListResponse{Items: []models.Item{}}
 	for _, item := range items {
 		res.Items = append(res.Items, *item)
 	}
@@ -139,63 +373,102 @@ func (s *PointlingService) ListItems(c context.Context) (models.ItemListResponse
 }
 
 func (s *PointlingService) GetItem(c context.Context, itemID string) (models.Item, error) {
-	id := parseID(itemID)
-	item, err := s.PointlingRepo.GetItemByID(id)
+	id, err := parseID("item_id", itemID)
+	if err != nil {
+		return models.Item{}, err
+	}
+	item, err := s.PointlingRepo.GetItemByID(c, id)
 	if err != nil {
 		return models.Item{}, err
 	}
+	if item == nil {
+		return models.Item{}, fmt.Errorf("%w: %d", models.ErrItemNotFound, id)
+	}
 	return *item, nil
 }
 
-func (s *PointlingService) CreateItem(c context.Context, req models.CreateItemRequest) (models.SuccessResponse, error) {
+func (s *PointlingService) CreateItem(c context.Context, req models.CreateItemRequest) (models.Item, error) {
+	if err := req.Validate(); err != nil {
+		return models.Item{}, err
+	}
 	item := &models.Item{
 		Name:        req.Name,
-		Category:    models.ItemCategory(req.Category),
-		Rarity:      models.ItemRarity(req.Rarity),
+		Category:    req.Category,
+		Rarity:      req.Rarity,
 		AssetID:     req.AssetID,
 		PricePoints: &req.Cost,
 	}
-	if err := s.PointlingRepo.CreateItem(item); err != nil {
-		return models.SuccessResponse{Success: false}, err
+	if item.Rarity == "" {
+		item.Rarity = models.RarityCommon
+	}
+	if req.Slot != "" {
+		item.Slot = &req.Slot
+	}
+	err := s.PointlingRepo.InTransaction(c, func(tx repository.API) error {
+		if err := tx.CreateItem(c, item); err != nil {
+			return err
+		}
+		return audit(c, tx, models.AuditActionItemCreate, "item", strconv.FormatInt(item.ItemID, 10), nil, item)
+	})
+	if err != nil {
+		return models.Item{}, err
 	}
-	return models.SuccessResponse{Success: true}, nil
+	return *item, nil
 }
 
 func (s *PointlingService) GetInventory(c context.Context, pointlingID string) (models.InventoryResponse, error) {
-	id := parseID(pointlingID)
-	items, err := s.PointlingRepo.GetItems(id, nil)
+	id, err := parseID("pointling_id", pointlingID)
 	if err != nil {
 		return models.InventoryResponse{}, err
 	}
-	var res models.InventoryResponse
+	items, err := s.PointlingRepo.GetItems(c, id, nil)
+	if err != nil {
+		return models.InventoryResponse{}, err
+	}
+	res := models.InventoryResponse{Items: []models.Item{}}
 	for _, pi := range items {
 		res.Items = append(res.Items, *pi.Item)
 	}
 	return res, nil
 }
 
-func (s *PointlingService) AcquireItem(c context.Context, req models.AcquireItemRequest) (models.SuccessResponse, error) {
-	if err := s.PointlingRepo.AddItem(parseID(req.PointlingID), parseID(req.ItemID)); err != nil {
-		return models.SuccessResponse{Success: false}, err
+func (s *PointlingService) ToggleEquipped(c context.Context, req models.ToggleEquippedRequest) (models.Pointling, error) {
+	var fe models.FieldErrors
+	pointlingID := fe.ParseID("pointling_id", req.PointlingID)
+	itemID := fe.ParseID("item_id", req.ItemID)
+	if err := fe.Err(); err != nil {
+		return models.Pointling{}, err
 	}
-	return models.SuccessResponse{Success: true}, nil
-}
 
-func (s *PointlingService) ToggleEquipped(c context.Context, req models.ToggleEquippedRequest) (models.Pointling, error) {
-	if err := s.PointlingRepo.ToggleEquipped(parseID(req.PointlingID), parseID(req.ItemID), req.Equipped); err != nil {
+	var updated models.Pointling
+	err := s.PointlingRepo.InTransaction(c, func(tx repository.API) error {
+		if err := tx.ToggleEquipped(c, pointlingID, itemID, req.Equipped); err != nil {
+			return err
+		}
+		p, err := refreshLook(c, tx, pointlingID)
+		if err != nil {
+			return err
+		}
+		updated = *p
+		return nil
+	})
+	if err != nil {
 		return models.Pointling{}, err
 	}
-	return s.GetPointling(c, req.PointlingID)
+	return updated, nil
 }
 
-func (s *PointlingService) SpendPoints(c context.Context, req models.SpendPointsRequest) (models.SuccessResponse, error) {
-	if err := s.PointlingRepo.SpendPoints(parseID(req.UserID), parseID(req.ItemID), req.Amount); err != nil {
-		return models.SuccessResponse{Success: false}, err
+func validateTimezone(tz string) error {
+	if tz == "" || tz == "Local" {
+		return models.ErrInvalidTimezone
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		return fmt.Errorf("%w: %s", models.ErrInvalidTimezone, tz)
 	}
-	return models.SuccessResponse{Success: true}, nil
+	return nil
 }
 
-func parseID(id string) int64 {
-	val, _ := strconv.ParseInt(id, 10, 64)
-	return val
+// parseID parses a single request or path ID named field.
+func parseID(field, id string) (int64, error) {
+	return models.ParseID(field, id)
 }
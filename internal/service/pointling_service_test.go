@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository/memory"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmptyItemListsEncodeAsArrays(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.New()
+	svc := New(repo)
+	require.NoError(t, repo.CreateUser(ctx, &models.User{UserID: 1, DisplayName: "one"}))
+	p := models.NewPointling(1, nil)
+	require.NoError(t, repo.CreatePointling(ctx, p))
+
+	items, err := svc.ListItems(ctx)
+	require.NoError(t, err)
+	body, err := json.Marshal(items)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"items": []}`, string(body))
+
+	inventory, err := svc.GetInventory(ctx, strconv.FormatInt(p.PointlingID, 10))
+	require.NoError(t, err)
+	body, err = json.Marshal(inventory)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"items": []}`, string(body))
+}
+
+func TestAddXPIgnoresClientGain(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.New()
+	svc := New(repo)
+	require.NoError(t, repo.CreateUser(ctx, &models.User{UserID: 1, DisplayName: "one"}))
+	p := models.NewPointling(1, nil)
+	require.NoError(t, repo.CreatePointling(ctx, p))
+
+	var req models.AddXPRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"source": "PLAY", "xp_gain": 1000}`), &req))
+	req.PointlingID = strconv.FormatInt(p.PointlingID, 10)
+	_, err := svc.AddXP(ctx, req)
+	require.NoError(t, err)
+
+	rule, ok := models.DefaultXPConfig().Rule(models.XPSourcePlay)
+	require.True(t, ok)
+	events, err := repo.GetEventsByPointling(ctx, models.XPHistoryFilter{PointlingID: p.PointlingID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, rule.PerAction, events[0].XPAmount)
+}
+
+func TestAddXPMilestoneGrantsOneColor(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.New()
+	svc := New(repo)
+	require.NoError(t, repo.CreateUser(ctx, &models.User{UserID: 1, DisplayName: "one"}))
+	p := models.NewPointling(1, nil)
+	p.Level, p.RequiredXP = models.ColorUnlockInterval-1, 1
+	require.NoError(t, repo.CreatePointling(ctx, p))
+	require.NoError(t, repo.AddPointlingColor(ctx, p.PointlingID, models.DefaultColor()))
+	createUnlock(t, repo, models.ColorUnlockInterval)
+
+	res, err := svc.AddXP(ctx, models.AddXPRequest{PointlingID: strconv.FormatInt(p.PointlingID, 10), Source: models.XPSourcePlay})
+	require.NoError(t, err)
+	require.Contains(t, res.LevelsCrossed, models.ColorUnlockInterval)
+	require.Equal(t, []string{models.ColorPalette[1]}, res.ColorsUnlocked)
+
+	colors, err := repo.GetPointlingColors(ctx, p.PointlingID)
+	require.NoError(t, err)
+	require.Len(t, colors, 2)
+
+	// The level's offer holds items only, so no second color can be claimed.
+	pending, err := repo.GetPendingRewards(ctx, p.PointlingID)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	for _, opt := range pending[0].Options {
+		require.Equal(t, models.RewardTypeItem, opt.Type)
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository"
+)
+
+// PurchaseItem buys an item for a pointling with its owner's points. The
+// ownership, price, level and duplicate checks, the debit, the spend record
+// and the grant all commit together or not at all.
+func (s *PointlingService) PurchaseItem(c context.Context, req models.PurchaseItemRequest) (models.TransactionSuccess, error) {
+	var fe models.FieldErrors
+	userID := fe.ParseID("user_id", req.UserID)
+	pointlingID := fe.ParseID("pointling_id", req.PointlingID)
+	itemID := fe.ParseID("item_id", req.ItemID)
+	if err := fe.Err(); err != nil {
+		return models.TransactionSuccess{}, err
+	}
+
+	var res models.TransactionSuccess
+	err := s.PointlingRepo.InTransaction(c, func(tx repository.API) error {
+		p, err := tx.LockPointling(c, pointlingID)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			return fmt.Errorf("%w: %d", models.ErrPointlingNotFound, pointlingID)
+		}
+		if p.UserID != userID {
+			return models.ErrNotPointlingOwner
+		}
+
+		item, err := tx.GetItemByID(c, itemID)
+		if err != nil {
+			return err
+		}
+		if item == nil {
+			return models.ErrItemNotFound
+		}
+		if item.PricePoints == nil || *item.PricePoints <= 0 {
+			return models.ErrItemNotForSale
+		}
+		if item.UnlockLevel != nil && p.Level < *item.UnlockLevel {
+			return models.ErrLevelTooLow
+		}
+
+		user, err := tx.GetUser(c, userID)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return fmt.Errorf("%w: %d", models.ErrUserNotFound, userID)
+		}
+		price := *item.PricePoints
+		if user.PointBalance < int64(price) {
+			return models.ErrInsufficientPoints
+		}
+
+		// Granting first surfaces ErrAlreadyOwned before any points move.
+		if err := tx.AddItem(c, pointlingID, itemID); err != nil {
+			return err
+		}
+
+		previous, err := tx.GetTotalSpentByUser(c, userID, models.TimeRange{})
+		if err != nil {
+			return err
+		}
+		spend := &models.PointSpend{
+			UserID:      userID,
+			PointlingID: &pointlingID,
+			ItemID:      itemID,
+			PointsSpent: price,
+		}
+		if err := tx.SpendPoints(c, spend); err != nil {
+			if errors.Is(err, models.ErrInsufficientBalance) {
+				return models.ErrInsufficientPoints
+			}
+			return err
+		}
+		if _, err := refreshLook(c, tx, pointlingID); err != nil {
+			return err
+		}
+
+		// The debit holds the user's row lock until commit, so this is the
+		// balance the purchase left even if other changes ran meanwhile.
+		after, err := tx.GetUser(c, userID)
+		if err != nil {
+			return err
+		}
+
+		previousSpend := int(previous)
+		res = models.TransactionSuccess{
+			ItemID:        itemID,
+			PointsSpent:   price,
+			NewBalance:    after.PointBalance,
+			PreviousSpend: &previousSpend,
+		}
+		return nil
+	})
+	if err != nil {
+		return models.TransactionSuccess{}, fmt.Errorf("purchase item: %w", err)
+	}
+	return res, nil
+}
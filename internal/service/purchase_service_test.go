@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository/memory"
+
+	"github.com/stretchr/testify/require"
+)
+
+// purchaseFixture is a user with balance points, their level 1 pointling and
+// a hat costing 30 points, adjusted by edit when given.
+type purchaseFixture struct {
+	repo      *memory.Repository
+	svc       *PointlingService
+	pointling *models.Pointling
+	hat       *models.Item
+}
+
+func newPurchaseFixture(t *testing.T, balance int64, edit func(*models.Item)) purchaseFixture {
+	t.Helper()
+	ctx := context.Background()
+	repo := memory.New()
+	require.NoError(t, repo.CreateUser(ctx, &models.User{UserID: 1, DisplayName: "one", PointBalance: balance}))
+	p := models.NewPointling(1, nil)
+	require.NoError(t, repo.CreatePointling(ctx, p))
+	slot, price := models.SlotHat, 30
+	hat := &models.Item{Name: "Hat", Category: models.CategoryAccessory, Slot: &slot, AssetID: "hat",
+		Rarity: models.RarityCommon, PricePoints: &price}
+	if edit != nil {
+		edit(hat)
+	}
+	require.NoError(t, repo.CreateItem(ctx, hat))
+	return purchaseFixture{repo: repo, svc: New(repo), pointling: p, hat: hat}
+}
+
+func (f purchaseFixture) buy(itemID int64) (models.TransactionSuccess, error) {
+	return f.svc.PurchaseItem(context.Background(), models.PurchaseItemRequest{
+		UserID:      "1",
+		PointlingID: strconv.FormatInt(f.pointling.PointlingID, 10),
+		ItemID:      strconv.FormatInt(itemID, 10),
+	})
+}
+
+func TestPurchaseItem(t *testing.T) {
+	ctx := context.Background()
+	f := newPurchaseFixture(t, 100, nil)
+	price := 20
+	scarf := &models.Item{Name: "Scarf", Category: models.CategoryFeature, AssetID: "scarf",
+		Rarity: models.RarityCommon, PricePoints: &price}
+	require.NoError(t, f.repo.CreateItem(ctx, scarf))
+
+	receipt, err := f.buy(f.hat.ItemID)
+	require.NoError(t, err)
+	require.Equal(t, 30, receipt.PointsSpent)
+	require.EqualValues(t, 70, receipt.NewBalance)
+	require.Equal(t, 0, *receipt.PreviousSpend)
+
+	receipt, err = f.buy(scarf.ItemID)
+	require.NoError(t, err)
+	require.EqualValues(t, 50, receipt.NewBalance)
+	require.Equal(t, 30, *receipt.PreviousSpend)
+
+	user, err := f.repo.GetUser(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, user.PointBalance, receipt.NewBalance)
+	owned, err := f.repo.GetItems(ctx, f.pointling.PointlingID, nil)
+	require.NoError(t, err)
+	require.Len(t, owned, 2)
+}
+
+func TestPurchaseItemRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		balance int64
+		editHat func(*models.Item)
+		setup   func(t *testing.T, f purchaseFixture)
+		want    error
+	}{
+		{
+			name:    "already owned",
+			balance: 100,
+			setup: func(t *testing.T, f purchaseFixture) {
+				require.NoError(t, f.repo.AddItem(context.Background(), f.pointling.PointlingID, f.hat.ItemID))
+			},
+			want: models.ErrAlreadyOwned,
+		},
+		{
+			name:    "insufficient points",
+			balance: 29,
+			want:    models.ErrInsufficientPoints,
+		},
+		{
+			name:    "level too low",
+			balance: 100,
+			editHat: func(hat *models.Item) {
+				level := 2
+				hat.UnlockLevel = &level
+			},
+			want: models.ErrLevelTooLow,
+		},
+		{
+			name:    "not for sale",
+			balance: 100,
+			editHat: func(hat *models.Item) {
+				hat.PricePoints = nil
+			},
+			want: models.ErrItemNotForSale,
+		},
+		{
+			name:    "someone else's pointling",
+			balance: 100,
+			setup: func(t *testing.T, f purchaseFixture) {
+				ctx := context.Background()
+				require.NoError(t, f.repo.CreateUser(ctx, &models.User{UserID: 2, DisplayName: "two"}))
+				other := models.NewPointling(2, nil)
+				require.NoError(t, f.repo.CreatePointling(ctx, other))
+				*f.pointling = *other
+			},
+			want: models.ErrNotPointlingOwner,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			f := newPurchaseFixture(t, tt.balance, tt.editHat)
+			if tt.setup != nil {
+				tt.setup(t, f)
+			}
+
+			_, err := f.buy(f.hat.ItemID)
+			require.ErrorIs(t, err, tt.want)
+
+			// Nothing moved.
+			user, err := f.repo.GetUser(ctx, 1)
+			require.NoError(t, err)
+			require.Equal(t, tt.balance, user.PointBalance)
+			spends, err := f.repo.GetByUser(ctx, models.SpendHistoryFilter{UserID: 1, Limit: 10})
+			require.NoError(t, err)
+			require.Empty(t, spends)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository"
+)
+
+// RefundSpend reverses a point spend: the points go back to the user through
+// the ledger, the item is taken off the pointling it was bought for and the
+// spend is flagged rather than deleted. Refunding the same spend again
+// returns the original reversal unchanged.
+func (s *PointlingService) RefundSpend(c context.Context, req models.RefundSpendRequest) (models.SpendReversal, error) {
+	var fe models.FieldErrors
+	spendID := fe.ParseID("spend_id", req.SpendID)
+	var pointlingID *int64
+	if req.PointlingID != nil {
+		id := fe.ParseID("pointling_id", *req.PointlingID)
+		pointlingID = &id
+	}
+	if err := fe.Err(); err != nil {
+		return models.SpendReversal{}, err
+	}
+
+	var res models.SpendReversal
+	err := s.PointlingRepo.InTransaction(c, func(tx repository.API) error {
+		// The row lock serializes concurrent refunds of the same spend.
+		spend, err := tx.LockPointSpend(c, spendID)
+		if err != nil {
+			return err
+		}
+		if spend == nil {
+			return models.ErrSpendNotFound
+		}
+		if spend.ReversedAt != nil {
+			existing, err := tx.GetSpendReversal(c, spendID)
+			if err != nil {
+				return err
+			}
+			if existing == nil {
+				return fmt.Errorf("spend %d is reversed but has no audit record", spendID)
+			}
+			res = *existing
+			res.AlreadyReversed = true
+			return nil
+		}
+
+		if spend.PointlingID != nil {
+			pointlingID = spend.PointlingID
+		} else if pointlingID != nil {
+			// A pointling named by the caller must be one the spender owns.
+			p, err := tx.GetPointlingByID(c, *pointlingID)
+			if err != nil {
+				return err
+			}
+			if p == nil {
+				return fmt.Errorf("%w: %d", models.ErrPointlingNotFound, *pointlingID)
+			}
+			if p.UserID != spend.UserID {
+				var fe models.FieldErrors
+				fe.Add("pointling_id", "must belong to the user who made the spend")
+				return fe.Err()
+			}
+		}
+
+		reference := models.SpendReference(spend.SpendID)
+		entry := &models.LedgerEntry{
+			UserID:    spend.UserID,
+			Delta:     int64(spend.PointsSpent),
+			Reason:    models.LedgerReasonRefund,
+			Reference: &reference,
+		}
+		if err := tx.PostLedgerEntry(c, entry); err != nil {
+			return err
+		}
+
+		revoked := false
+		if pointlingID != nil {
+			// Deleting the inventory row unequips the item along with it.
+			revoked, err = tx.RemoveItem(c, *pointlingID, spend.ItemID)
+			if err != nil {
+				return err
+			}
+			if revoked {
+				if _, err := refreshLook(c, tx, *pointlingID); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := tx.MarkSpendReversed(c, spend.SpendID); err != nil {
+			return err
+		}
+		res = models.SpendReversal{
+			SpendID:        spend.SpendID,
+			UserID:         spend.UserID,
+			PointlingID:    pointlingID,
+			ItemID:         spend.ItemID,
+			PointsRefunded: spend.PointsSpent,
+			LedgerEntryID:  entry.EntryID,
+			ItemRevoked:    revoked,
+			Reason:         req.Reason,
+			Actor:          auditActor(c),
+		}
+		if err := tx.CreateSpendReversal(c, &res); err != nil {
+			return err
+		}
+		return audit(c, tx, models.AuditActionSpendRefund, "point_spend", strconv.FormatInt(spend.SpendID, 10), spend, res)
+	})
+	if err != nil {
+		return models.SpendReversal{}, fmt.Errorf("refund spend: %w", err)
+	}
+	return res, nil
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"my-pointlings-be/internal/auth"
+	"my-pointlings-be/internal/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+// refundFixture buys the purchase fixture's hat and returns the spend it
+// recorded.
+func refundFixture(t *testing.T) (purchaseFixture, *models.PointSpend) {
+	t.Helper()
+	f := newPurchaseFixture(t, 100, nil)
+	_, err := f.buy(f.hat.ItemID)
+	require.NoError(t, err)
+	spends, err := f.repo.GetByUser(context.Background(), models.SpendHistoryFilter{UserID: 1, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, spends, 1)
+	return f, spends[0]
+}
+
+func TestRefundSpend(t *testing.T) {
+	f, spend := refundFixture(t)
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{APIKey: "ops"})
+
+	res, err := f.svc.RefundSpend(ctx, models.RefundSpendRequest{
+		SpendID: strconv.FormatInt(spend.SpendID, 10),
+		Reason:  "bought by mistake",
+	})
+	require.NoError(t, err)
+	require.False(t, res.AlreadyReversed)
+	require.Equal(t, 30, res.PointsRefunded)
+	require.True(t, res.ItemRevoked)
+	require.Equal(t, "api-key:ops", res.Actor)
+
+	// The ledger reverses exactly what was spent.
+	entries, err := f.repo.GetLedgerEntries(ctx, 1, 0, 10)
+	require.NoError(t, err)
+	require.Equal(t, res.LedgerEntryID, entries[0].EntryID)
+	require.Equal(t, models.LedgerReasonRefund, entries[0].Reason)
+	require.EqualValues(t, 30, entries[0].Delta)
+	require.EqualValues(t, 100, entries[0].BalanceAfter)
+	require.Equal(t, models.SpendReference(spend.SpendID), *entries[0].Reference)
+	user, err := f.repo.GetUser(ctx, 1)
+	require.NoError(t, err)
+	require.EqualValues(t, 100, user.PointBalance)
+
+	owned, err := f.repo.GetItems(ctx, f.pointling.PointlingID, nil)
+	require.NoError(t, err)
+	require.Empty(t, owned)
+
+	audit, err := f.repo.ListAuditEntries(ctx, models.AuditLogFilter{Limit: 10, TargetType: "point_spend"})
+	require.NoError(t, err)
+	require.Len(t, audit, 1)
+	require.Equal(t, "api-key:ops", audit[0].Actor)
+	require.Equal(t, models.AuditActionSpendRefund, audit[0].Action)
+	require.Equal(t, strconv.FormatInt(spend.SpendID, 10), audit[0].TargetID)
+	var after models.SpendReversal
+	require.NoError(t, json.Unmarshal(audit[0].After, &after))
+	require.Equal(t, 30, after.PointsRefunded)
+	require.Equal(t, "bought by mistake", after.Reason)
+}
+
+func TestRefundSpendTwice(t *testing.T) {
+	f, spend := refundFixture(t)
+	ctx := context.Background()
+	req := models.RefundSpendRequest{SpendID: strconv.FormatInt(spend.SpendID, 10), Reason: "duplicate"}
+
+	first, err := f.svc.RefundSpend(ctx, req)
+	require.NoError(t, err)
+	again, err := f.svc.RefundSpend(ctx, req)
+	require.NoError(t, err)
+	require.True(t, again.AlreadyReversed)
+	require.Equal(t, first.LedgerEntryID, again.LedgerEntryID)
+
+	// The second call moved no points and wrote no second audit entry.
+	user, err := f.repo.GetUser(ctx, 1)
+	require.NoError(t, err)
+	require.EqualValues(t, 100, user.PointBalance)
+	entries, err := f.repo.GetLedgerEntries(ctx, 1, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	audit, err := f.repo.ListAuditEntries(ctx, models.AuditLogFilter{Limit: 10, TargetType: "point_spend"})
+	require.NoError(t, err)
+	require.Len(t, audit, 1)
+	require.Equal(t, "system", audit[0].Actor)
+}
+
+func TestRefundSpendNotFound(t *testing.T) {
+	f, spend := refundFixture(t)
+	ctx := context.Background()
+
+	_, err := f.svc.RefundSpend(ctx, models.RefundSpendRequest{
+		SpendID: strconv.FormatInt(spend.SpendID+1, 10),
+		Reason:  "unknown",
+	})
+	require.ErrorIs(t, err, models.ErrSpendNotFound)
+
+	user, err := f.repo.GetUser(ctx, 1)
+	require.NoError(t, err)
+	require.EqualValues(t, 70, user.PointBalance)
+	audit, err := f.repo.ListAuditEntries(ctx, models.AuditLogFilter{Limit: 10, TargetType: "point_spend"})
+	require.NoError(t, err)
+	require.Empty(t, audit)
+}
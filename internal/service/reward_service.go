@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository"
+)
+
+func (s *PointlingService) GetPendingRewards(c context.Context, pointlingID string) (models.PendingRewardsResponse, error) {
+	id, err := parseID("pointling_id", pointlingID)
+	if err != nil {
+		return models.PendingRewardsResponse{}, err
+	}
+	rewards, err := s.PointlingRepo.GetPendingRewards(c, id)
+	if err != nil {
+		return models.PendingRewardsResponse{}, err
+	}
+	res := models.PendingRewardsResponse{Rewards: []models.LevelUpOptionsResponse{}}
+	for _, r := range rewards {
+		res.Rewards = append(res.Rewards, r.ToOptionsResponse())
+	}
+	return res, nil
+}
+
+func (s *PointlingService) ClaimReward(c context.Context, req models.ClaimRewardRequest) (models.LevelReward, error) {
+	var fe models.FieldErrors
+	pointlingID := fe.ParseID("pointling_id", req.PointlingID)
+	rewardID := fe.ParseID("reward_id", req.RewardID)
+	if err := fe.Err(); err != nil {
+		return models.LevelReward{}, err
+	}
+
+	var claimed models.LevelReward
+	err := s.PointlingRepo.InTransaction(c, func(tx repository.API) error {
+		reward, err := tx.GetLevelReward(c, rewardID)
+		if err != nil {
+			return err
+		}
+		if reward == nil || reward.PointlingID != pointlingID {
+			return models.ErrRewardNotFound
+		}
+		if reward.ClaimedAt != nil {
+			return models.ErrRewardAlreadyClaimed
+		}
+		if !time.Now().Before(reward.ExpiresAt) {
+			return models.ErrRewardExpired
+		}
+		if !reward.HasOption(req.Option) {
+			return models.ErrInvalidRewardOption
+		}
+
+		if err := tx.ClaimLevelReward(c, rewardID, req.Option); err != nil {
+			return err
+		}
+		granted, err := grantRewardOption(c, tx, pointlingID, req.Option)
+		if err != nil {
+			return err
+		}
+		if granted && req.Option.Type == models.RewardTypeItem {
+			if _, err := refreshLook(c, tx, pointlingID); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		reward.ChosenOption = &req.Option
+		reward.ClaimedAt = &now
+		claimed = *reward
+		return nil
+	})
+	if err != nil {
+		return models.LevelReward{}, fmt.Errorf("claim reward: %w", err)
+	}
+	return claimed, nil
+}
+
+// offerLevelReward draws up to RewardOptionsPerLevel items the pointling does
+// not own yet from the level's unlocks and stores them as a pending offer.
+// Colors are not offered: each milestone grants exactly one, automatically.
+// It returns nil when there is nothing to offer.
+func offerLevelReward(c context.Context, repo repository.API, pointlingID int64, level int) (*models.LevelReward, error) {
+	unlocks, err := repo.GetUnlocksForLevel(c, level)
+	if err != nil {
+		return nil, err
+	}
+	ownedItems, err := repo.GetItems(c, pointlingID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := make(map[models.RewardOption]bool)
+	for _, pi := range ownedItems {
+		owned[models.RewardOption{Type: models.RewardTypeItem, ID: strconv.FormatInt(pi.ItemID, 10)}] = true
+	}
+
+	var pool []models.RewardOption
+	for _, item := range unlocks {
+		opt := models.RewardOption{Type: models.RewardTypeItem, ID: strconv.FormatInt(item.ItemID, 10)}
+		if !owned[opt] {
+			pool = append(pool, opt)
+		}
+	}
+	if len(pool) == 0 {
+		return nil, nil
+	}
+
+	rand.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+	if len(pool) > models.RewardOptionsPerLevel {
+		pool = pool[:models.RewardOptionsPerLevel]
+	}
+
+	reward := &models.LevelReward{
+		PointlingID: pointlingID,
+		Level:       level,
+		Options:     pool,
+		ExpiresAt:   time.Now().Add(models.RewardOfferTTL),
+	}
+	if err := repo.CreateLevelReward(c, reward); err != nil {
+		return nil, err
+	}
+	return reward, nil
+}
+
+// grantRewardOption gives the pointling the chosen option and reports whether
+// it was new. An option the pointling came to own after the offer was made,
+// by purchase or a milestone, counts as already granted. Color options only
+// appear in offers stored before milestones granted colors on their own.
+func grantRewardOption(c context.Context, repo repository.API, pointlingID int64, opt models.RewardOption) (bool, error) {
+	var err error
+	switch opt.Type {
+	case models.RewardTypeItem:
+		itemID, parseErr := strconv.ParseInt(opt.ID, 10, 64)
+		if parseErr != nil {
+			return false, models.ErrInvalidRewardOption
+		}
+		err = repo.AddItem(c, pointlingID, itemID)
+	case models.RewardTypeColor:
+		err = repo.AddPointlingColor(c, pointlingID, opt.ID)
+	default:
+		return false, models.ErrInvalidRewardOption
+	}
+	if errors.Is(err, models.ErrAlreadyOwned) {
+		return false, nil
+	}
+	return err == nil, err
+}
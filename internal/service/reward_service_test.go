@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository/memory"
+
+	"github.com/stretchr/testify/require"
+)
+
+// createUnlock adds a feature item that unlocks at level.
+func createUnlock(t *testing.T, repo *memory.Repository, level int) *models.Item {
+	t.Helper()
+	item := &models.Item{Name: "Glow", Category: models.CategoryFeature, AssetID: "glow", Rarity: models.RarityCommon, UnlockLevel: &level}
+	require.NoError(t, repo.CreateItem(context.Background(), item))
+	return item
+}
+
+func TestOfferLevelRewardOffersOnlyItems(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.New()
+	require.NoError(t, repo.CreateUser(ctx, &models.User{UserID: 1, DisplayName: "one"}))
+	p := models.NewPointling(1, nil)
+	require.NoError(t, repo.CreatePointling(ctx, p))
+
+	// A milestone with no item unlocks has nothing to offer.
+	reward, err := offerLevelReward(ctx, repo, p.PointlingID, models.ColorUnlockInterval)
+	require.NoError(t, err)
+	require.Nil(t, reward)
+
+	item := createUnlock(t, repo, models.ColorUnlockInterval*2)
+	reward, err = offerLevelReward(ctx, repo, p.PointlingID, models.ColorUnlockInterval*2)
+	require.NoError(t, err)
+	require.Equal(t, []models.RewardOption{{Type: models.RewardTypeItem, ID: strconv.FormatInt(item.ItemID, 10)}}, reward.Options)
+}
+
+func TestClaimRewardAlreadyOwnedOption(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.New()
+	svc := New(repo)
+	require.NoError(t, repo.CreateUser(ctx, &models.User{UserID: 1, DisplayName: "one"}))
+	p := models.NewPointling(1, nil)
+	require.NoError(t, repo.CreatePointling(ctx, p))
+	item := createUnlock(t, repo, 2)
+
+	reward, err := offerLevelReward(ctx, repo, p.PointlingID, 2)
+	require.NoError(t, err)
+	option := reward.Options[0]
+	// The item is bought before the offer is claimed.
+	require.NoError(t, repo.AddItem(ctx, p.PointlingID, item.ItemID))
+
+	claimed, err := svc.ClaimReward(ctx, models.ClaimRewardRequest{
+		PointlingID: strconv.FormatInt(p.PointlingID, 10),
+		RewardID:    strconv.FormatInt(reward.RewardID, 10),
+		Option:      option,
+	})
+	require.NoError(t, err)
+	require.Equal(t, option, *claimed.ChosenOption)
+
+	pending, err := repo.GetPendingRewards(ctx, p.PointlingID)
+	require.NoError(t, err)
+	require.Empty(t, pending)
+}
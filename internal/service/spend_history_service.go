@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"my-pointlings-be/internal/models"
+)
+
+func (s *PointlingService) GetSpendHistory(c context.Context, req models.SpendHistoryRequest) (models.SpendHistoryResponse, error) {
+	filter, err := buildSpendHistoryFilter(req)
+	if err != nil {
+		return models.SpendHistoryResponse{}, err
+	}
+	// The total covers the same date range as the page, across all pages.
+	total, err := s.PointlingRepo.GetTotalSpentByUser(c, filter.UserID, filter.TimeRange)
+	if err != nil {
+		return models.SpendHistoryResponse{}, err
+	}
+
+	// Fetch one extra row to learn whether another page follows.
+	pageSize := filter.Limit
+	filter.Limit++
+	spends, err := s.PointlingRepo.GetByUser(c, filter)
+	if err != nil {
+		return models.SpendHistoryResponse{}, err
+	}
+
+	res := models.SpendHistoryResponse{Spends: []models.PointSpend{}, TotalSpent: total}
+	if len(spends) > pageSize {
+		spends = spends[:pageSize]
+		res.NextCursor = models.SpendCursorAfter(spends[pageSize-1]).Encode()
+	}
+	for _, spend := range spends {
+		res.Spends = append(res.Spends, *spend)
+	}
+	return res, nil
+}
+
+// ExportSpendHistory returns every spend matching the request's date range,
+// ignoring its paging, for CSV export.
+func (s *PointlingService) ExportSpendHistory(c context.Context, req models.SpendHistoryRequest) ([]models.PointSpend, error) {
+	req.Cursor, req.Limit, req.Offset = "", 0, 0
+	filter, err := buildSpendHistoryFilter(req)
+	if err != nil {
+		return nil, err
+	}
+	filter.Limit = models.MaxSpendHistoryLimit
+
+	all := []models.PointSpend{}
+	for {
+		spends, err := s.PointlingRepo.GetByUser(c, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, spend := range spends {
+			all = append(all, *spend)
+		}
+		if len(spends) < filter.Limit {
+			return all, nil
+		}
+		cursor := models.SpendCursorAfter(spends[len(spends)-1])
+		filter.Cursor = &cursor
+	}
+}
+
+func buildSpendHistoryFilter(req models.SpendHistoryRequest) (models.SpendHistoryFilter, error) {
+	userID, err := parseID("user_id", req.UserID)
+	if err != nil {
+		return models.SpendHistoryFilter{}, err
+	}
+	filter := models.SpendHistoryFilter{
+		UserID: userID,
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = models.DefaultSpendHistoryLimit
+	}
+	if filter.Limit > models.MaxSpendHistoryLimit {
+		filter.Limit = models.MaxSpendHistoryLimit
+	}
+	if filter.Offset < 0 {
+		return filter, fmt.Errorf("%w: offset must not be negative", models.ErrInvalidSpendHistoryQuery)
+	}
+
+	filter.TimeRange, err = models.ParseTimeRange(req.From, req.To, models.ErrInvalidSpendHistoryQuery)
+	if err != nil {
+		return filter, err
+	}
+
+	if req.Cursor != "" {
+		if filter.Offset > 0 {
+			return filter, fmt.Errorf("%w: use either cursor or offset", models.ErrInvalidSpendHistoryQuery)
+		}
+		cursor, err := models.DecodeHistoryCursor(req.Cursor, models.ErrInvalidSpendHistoryQuery)
+		if err != nil {
+			return filter, err
+		}
+		filter.Cursor = cursor
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"my-pointlings-be/internal/database/databasetest"
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository"
+	"my-pointlings-be/internal/repository/memory"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testServer *databasetest.Server
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	testServer = databasetest.Start()
+	code := m.Run()
+	testServer.Stop()
+	os.Exit(code)
+}
+
+func TestAddXPConcurrentGainsRespectCapAndLevels(t *testing.T) {
+	t.Run("Memory", func(t *testing.T) {
+		testAddXPConcurrent(t, memory.New())
+	})
+	t.Run("Postgres", func(t *testing.T) {
+		testAddXPConcurrent(t, repository.New(testServer.Open(t)))
+	})
+}
+
+func testAddXPConcurrent(t *testing.T, repo repository.API) {
+	ctx := context.Background()
+	svc := New(repo)
+
+	cfg, err := activeXPConfig(ctx, repo)
+	require.NoError(t, err)
+	rule, ok := cfg.Rule(models.XPSourcePlay)
+	require.True(t, ok)
+
+	user := &models.User{UserID: time.Now().UnixNano(), DisplayName: "xp-race"}
+	require.NoError(t, repo.CreateUser(ctx, user))
+	pointling := models.NewPointling(user.UserID, nil)
+	pointling.RequiredXP = cfg.Curve.RequiredXP(1)
+	require.NoError(t, repo.CreatePointling(ctx, pointling))
+
+	const workers = 40
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+		capped    int
+		failures  []error
+	)
+	start := make(chan struct{})
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, err := svc.AddXP(ctx, models.AddXPRequest{
+				PointlingID: strconv.FormatInt(pointling.PointlingID, 10),
+				Source:      models.XPSourcePlay,
+			})
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, models.ErrDailyXPLimitReached):
+				capped++
+			default:
+				failures = append(failures, err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	require.Empty(t, failures)
+	wantGains := rule.MaxDaily / rule.PerAction
+	require.Equal(t, wantGains, succeeded)
+	require.Equal(t, workers-wantGains, capped)
+
+	window := models.DailyWindow(time.Now(), user.Location())
+	earned, err := repo.GetDailyXPBySource(ctx, pointling.PointlingID, models.XPSourcePlay, window)
+	require.NoError(t, err)
+	require.Equal(t, wantGains*rule.PerAction, earned)
+
+	// Replaying the accepted gains serially must land on the stored state.
+	want := models.NewPointling(user.UserID, nil)
+	want.RequiredXP = cfg.Curve.RequiredXP(1)
+	for i := 0; i < wantGains; i++ {
+		want.ApplyXP(rule.PerAction, cfg.Curve)
+	}
+
+	got, err := repo.GetPointlingByID(ctx, pointling.PointlingID)
+	require.NoError(t, err)
+	require.Equal(t, want.Level, got.Level)
+	require.Equal(t, want.CurrentXP, got.CurrentXP)
+	require.Equal(t, want.RequiredXP, got.RequiredXP)
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository"
+)
+
+const xpConfigHistoryLimit = 50
+
+func (s *PointlingService) GetXPConfig(c context.Context) (models.XPConfig, error) {
+	cfg, err := activeXPConfig(c, s.PointlingRepo)
+	if err != nil {
+		return models.XPConfig{}, err
+	}
+	return *cfg, nil
+}
+
+func (s *PointlingService) ListXPConfigVersions(c context.Context) (models.XPConfigListResponse, error) {
+	configs, err := s.PointlingRepo.ListXPConfigVersions(c, xpConfigHistoryLimit)
+	if err != nil {
+		return models.XPConfigListResponse{}, err
+	}
+	res := models.XPConfigListResponse{Versions: []models.XPConfig{}}
+	for _, cfg := range configs {
+		res.Versions = append(res.Versions, *cfg)
+	}
+	return res, nil
+}
+
+func (s *PointlingService) UpdateXPConfig(c context.Context, req models.UpdateXPConfigRequest) (models.XPConfig, error) {
+	cfg := &models.XPConfig{
+		Curve:   req.Curve,
+		Sources: req.Sources,
+	}
+	if err := cfg.Validate(); err != nil {
+		return models.XPConfig{}, err
+	}
+	err := s.PointlingRepo.InTransaction(c, func(tx repository.API) error {
+		before, err := activeXPConfig(c, tx)
+		if err != nil {
+			return err
+		}
+		if err := tx.CreateXPConfigVersion(c, cfg); err != nil {
+			return err
+		}
+		return audit(c, tx, models.AuditActionXPConfigUpdate, "xp_config", strconv.Itoa(cfg.Version), before, cfg)
+	})
+	if err != nil {
+		return models.XPConfig{}, fmt.Errorf("update xp config: %w", err)
+	}
+	return *cfg, nil
+}
+
+// activeXPConfig returns the newest stored config, or the built-in defaults
+// when none has been stored yet.
+func activeXPConfig(c context.Context, repo repository.API) (*models.XPConfig, error) {
+	cfg, err := repo.GetActiveXPConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		return models.DefaultXPConfig(), nil
+	}
+	return cfg, nil
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"my-pointlings-be/internal/models"
+)
+
+func (s *PointlingService) GetXPHistory(c context.Context, req models.XPHistoryRequest) (models.XPHistoryResponse, error) {
+	cfg, err := activeXPConfig(c, s.PointlingRepo)
+	if err != nil {
+		return models.XPHistoryResponse{}, err
+	}
+	filter, err := buildXPHistoryFilter(req, cfg)
+	if err != nil {
+		return models.XPHistoryResponse{}, err
+	}
+
+	switch req.Aggregate {
+	case "":
+		return s.listXPEvents(c, filter)
+	case models.XPHistoryAggregateDaily:
+		_, user, err := s.pointlingOwner(c, filter.PointlingID)
+		if err != nil {
+			return models.XPHistoryResponse{}, err
+		}
+		// Days are bucketed in the owner's timezone so they match the cap windows.
+		loc := user.Location()
+		filter.TimeZone = loc.String()
+		if filter.From == nil {
+			today := models.DailyWindow(s.Clock(), loc)
+			from := today.Start.AddDate(0, 0, -(models.DefaultXPHistoryDays - 1))
+			filter.From = &from
+		}
+		totals, err := s.PointlingRepo.GetDailyXPTotals(c, filter)
+		if err != nil {
+			return models.XPHistoryResponse{}, err
+		}
+		res := models.XPHistoryResponse{Daily: []models.XPDailyTotal{}}
+		for _, t := range totals {
+			res.Daily = append(res.Daily, *t)
+		}
+		return res, nil
+	default:
+		return models.XPHistoryResponse{}, fmt.Errorf("%w: unknown aggregate %q", models.ErrInvalidXPHistoryQuery, req.Aggregate)
+	}
+}
+
+func (s *PointlingService) GetXPLimits(c context.Context, pointlingID string) (models.XPLimitsResponse, error) {
+	id, err := parseID("pointling_id", pointlingID)
+	if err != nil {
+		return models.XPLimitsResponse{}, err
+	}
+	p, user, err := s.pointlingOwner(c, id)
+	if err != nil {
+		return models.XPLimitsResponse{}, err
+	}
+	personality, err := pointlingPersonality(c, s.PointlingRepo, p)
+	if err != nil {
+		return models.XPLimitsResponse{}, err
+	}
+
+	cfg, err := activeXPConfig(c, s.PointlingRepo)
+	if err != nil {
+		return models.XPLimitsResponse{}, err
+	}
+
+	loc := user.Location()
+	window := models.DailyWindow(s.Clock(), loc)
+	res := models.XPLimitsResponse{
+		Timezone: loc.String(),
+		ResetAt:  window.End,
+		Limits:   []models.XPSourceLimit{},
+	}
+	for _, source := range cfg.SourceNames() {
+		earned, err := s.PointlingRepo.GetDailyXPBySource(c, id, source, window)
+		if err != nil {
+			return models.XPLimitsResponse{}, err
+		}
+		maxDaily := personality.BoostRule(source, cfg.Sources[source]).MaxDaily
+		res.Limits = append(res.Limits, models.XPSourceLimit{
+			Source:      source,
+			MaxDaily:    maxDaily,
+			EarnedToday: earned,
+			Remaining:   remainingXP(maxDaily, earned),
+		})
+	}
+	return res, nil
+}
+
+// pointlingOwner loads a pointling together with the user that owns it.
+func (s *PointlingService) pointlingOwner(c context.Context, pointlingID int64) (*models.Pointling, *models.User, error) {
+	p, err := s.PointlingRepo.GetPointlingByID(c, pointlingID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if p == nil {
+		return nil, nil, fmt.Errorf("%w: %d", models.ErrPointlingNotFound, pointlingID)
+	}
+	user, err := s.PointlingRepo.GetUser(c, p.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil {
+		return nil, nil, fmt.Errorf("%w: %d", models.ErrUserNotFound, p.UserID)
+	}
+	return p, user, nil
+}
+
+func remainingXP(maxDaily, earned int) int {
+	if earned >= maxDaily {
+		return 0
+	}
+	return maxDaily - earned
+}
+
+func (s *PointlingService) listXPEvents(c context.Context, filter models.XPHistoryFilter) (models.XPHistoryResponse, error) {
+	// Fetch one extra row to learn whether another page follows.
+	pageSize := filter.Limit
+	filter.Limit++
+	events, err := s.PointlingRepo.GetEventsByPointling(c, filter)
+	if err != nil {
+		return models.XPHistoryResponse{}, err
+	}
+
+	res := models.XPHistoryResponse{Events: []models.XPEvent{}}
+	if len(events) > pageSize {
+		events = events[:pageSize]
+		res.NextCursor = models.CursorAfter(events[pageSize-1]).Encode()
+	}
+	for _, e := range events {
+		res.Events = append(res.Events, *e)
+	}
+	return res, nil
+}
+
+func buildXPHistoryFilter(req models.XPHistoryRequest, cfg *models.XPConfig) (models.XPHistoryFilter, error) {
+	pointlingID, err := parseID("pointling_id", req.PointlingID)
+	if err != nil {
+		return models.XPHistoryFilter{}, err
+	}
+	filter := models.XPHistoryFilter{
+		PointlingID: pointlingID,
+		Limit:       req.Limit,
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = models.DefaultXPHistoryLimit
+	}
+	if filter.Limit > models.MaxXPHistoryLimit {
+		filter.Limit = models.MaxXPHistoryLimit
+	}
+
+	if req.Source != "" {
+		source := models.XPEventSource(req.Source)
+		if _, ok := cfg.Rule(source); !ok {
+			return filter, models.ErrInvalidXPSource
+		}
+		filter.Source = &source
+	}
+
+	filter.TimeRange, err = models.ParseTimeRange(req.From, req.To, models.ErrInvalidXPHistoryQuery)
+	if err != nil {
+		return filter, err
+	}
+
+	if req.Cursor != "" {
+		cursor, err := models.DecodeHistoryCursor(req.Cursor, models.ErrInvalidXPHistoryQuery)
+		if err != nil {
+			return filter, err
+		}
+		filter.Cursor = cursor
+	}
+
+	return filter, nil
+}
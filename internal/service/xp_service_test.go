@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"my-pointlings-be/internal/models"
+	"my-pointlings-be/internal/repository/memory"
+
+	"github.com/stretchr/testify/require"
+)
+
+// xpFixture is a user in timezone with one pointling, on a service and
+// memory repository that both read the time from now.
+type xpFixture struct {
+	repo      *memory.Repository
+	svc       *PointlingService
+	pointling string
+	now       *time.Time
+}
+
+func newXPFixture(t *testing.T, timezone string, start time.Time) xpFixture {
+	t.Helper()
+	ctx := context.Background()
+	now := start
+	repo := memory.New()
+	repo.SetClock(func() time.Time { return now })
+	svc := New(repo)
+	svc.Clock = func() time.Time { return now }
+
+	require.NoError(t, repo.CreateUser(ctx, &models.User{UserID: 1, DisplayName: "one", Timezone: timezone}))
+	p := models.NewPointling(1, nil)
+	require.NoError(t, repo.CreatePointling(ctx, p))
+	return xpFixture{repo: repo, svc: svc, pointling: strconv.FormatInt(p.PointlingID, 10), now: &now}
+}
+
+func (f xpFixture) play() (models.XPUpdateResponse, error) {
+	return f.svc.AddXP(context.Background(), models.AddXPRequest{PointlingID: f.pointling, Source: models.XPSourcePlay})
+}
+
+func (f xpFixture) remaining(t *testing.T, source models.XPEventSource) (int, time.Time) {
+	t.Helper()
+	limits, err := f.svc.GetXPLimits(context.Background(), f.pointling)
+	require.NoError(t, err)
+	for _, l := range limits.Limits {
+		if l.Source == source {
+			return l.Remaining, limits.ResetAt
+		}
+	}
+	t.Fatalf("no limit for %s", source)
+	return 0, time.Time{}
+}
+
+func TestAddXPCapFollowsUserDayAcrossDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	// Clocks fall back on this day, so it is 25 hours long.
+	f := newXPFixture(t, "America/New_York", time.Date(2026, 11, 1, 0, 30, 0, 0, ny))
+	perAction, maxDaily := models.XPSourcePlay.GetXPPerAction(), models.XPSourcePlay.GetMaxDailyXP()
+
+	for i := 1; i < maxDaily/perAction; i++ {
+		_, err := f.play()
+		require.NoError(t, err)
+	}
+
+	// 23:30 is more than 24 hours after local midnight but still the same day.
+	*f.now = time.Date(2026, 11, 1, 23, 30, 0, 0, ny)
+	_, err = f.play()
+	require.NoError(t, err)
+	_, err = f.play()
+	require.ErrorIs(t, err, models.ErrDailyXPLimitReached)
+	remaining, resetAt := f.remaining(t, models.XPSourcePlay)
+	require.Zero(t, remaining)
+	require.True(t, resetAt.Equal(time.Date(2026, 11, 2, 0, 0, 0, 0, ny)), "reset at %s", resetAt)
+
+	// The next local day starts a fresh cap.
+	*f.now = time.Date(2026, 11, 2, 0, 10, 0, 0, ny)
+	_, err = f.play()
+	require.NoError(t, err)
+	remaining, _ = f.remaining(t, models.XPSourcePlay)
+	require.Equal(t, maxDaily-perAction, remaining)
+}
+
+func TestAddXPCapUsesUserZoneNotUTC(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	// 23:50 UTC on the 16th is already the 17th in Tokyo.
+	f := newXPFixture(t, "Asia/Tokyo", time.Date(2026, 10, 16, 23, 50, 0, 0, time.UTC))
+
+	_, err = f.svc.AddXP(context.Background(), models.AddXPRequest{PointlingID: f.pointling, Source: models.XPSourceDaily})
+	require.NoError(t, err)
+
+	// Ten minutes later it is a new UTC day, but not a new Tokyo day.
+	*f.now = time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	_, err = f.svc.AddXP(context.Background(), models.AddXPRequest{PointlingID: f.pointling, Source: models.XPSourceDaily})
+	require.ErrorIs(t, err, models.ErrDailyXPLimitReached)
+	_, resetAt := f.remaining(t, models.XPSourceDaily)
+	require.True(t, resetAt.Equal(time.Date(2026, 10, 18, 0, 0, 0, 0, tokyo)), "reset at %s", resetAt)
+}
+
+func TestAddXPPartialGainAtCap(t *testing.T) {
+	ctx := context.Background()
+	f := newXPFixture(t, "UTC", time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC))
+	cfg := models.DefaultXPConfig()
+	cfg.Sources[models.XPSourcePlay] = models.XPSourceRule{MaxDaily: 50, PerAction: 20}
+	require.NoError(t, f.repo.CreateXPConfigVersion(ctx, cfg))
+
+	for _, want := range []int{20, 20, 10} {
+		res, err := f.play()
+		require.NoError(t, err)
+		require.Equal(t, want, res.XPGained)
+	}
+	_, err := f.play()
+	require.ErrorIs(t, err, models.ErrDailyXPLimitReached)
+
+	// Only what was granted reached the pointling.
+	id, err := strconv.ParseInt(f.pointling, 10, 64)
+	require.NoError(t, err)
+	want := models.NewPointling(1, nil)
+	want.ApplyXP(50, cfg.Curve)
+	got, err := f.repo.GetPointlingByID(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, want.Level, got.Level)
+	require.Equal(t, want.CurrentXP, got.CurrentXP)
+	remaining, _ := f.remaining(t, models.XPSourcePlay)
+	require.Zero(t, remaining)
+}
+
+// addEvents records n PLAY events worth 1..n XP, oldest first.
+func (f xpFixture) addEvents(t *testing.T, n int) {
+	t.Helper()
+	id, err := strconv.ParseInt(f.pointling, 10, 64)
+	require.NoError(t, err)
+	limit := models.XPDailyCap{Window: models.DailyWindow(*f.now, time.UTC), Max: n * n}
+	for i := 1; i <= n; i++ {
+		require.NoError(t, f.repo.AddXP(context.Background(), &models.XPEvent{PointlingID: id, Source: models.XPSourcePlay, XPAmount: i}, limit))
+	}
+}
+
+func TestGetXPHistoryPaging(t *testing.T) {
+	ctx := context.Background()
+	f := newXPFixture(t, "UTC", time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC))
+	f.addEvents(t, 5)
+
+	var amounts []int
+	var pages int
+	cursor := ""
+	for {
+		res, err := f.svc.GetXPHistory(ctx, models.XPHistoryRequest{PointlingID: f.pointling, Limit: 2, Cursor: cursor})
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(res.Events), 2)
+		for _, e := range res.Events {
+			amounts = append(amounts, e.XPAmount)
+		}
+		pages++
+		if res.NextCursor == "" {
+			break
+		}
+		cursor = res.NextCursor
+	}
+	// Newest first, every event exactly once, and no empty trailing page.
+	require.Equal(t, []int{5, 4, 3, 2, 1}, amounts)
+	require.Equal(t, 3, pages)
+
+	_, err := f.svc.GetXPHistory(ctx, models.XPHistoryRequest{PointlingID: f.pointling, Cursor: "not-a-cursor"})
+	require.ErrorIs(t, err, models.ErrInvalidXPHistoryQuery)
+}
+
+func TestGetXPHistoryLimitBounds(t *testing.T) {
+	f := newXPFixture(t, "UTC", time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC))
+	f.addEvents(t, models.MaxXPHistoryLimit+5)
+
+	tests := []struct {
+		name  string
+		limit int
+		want  int
+	}{
+		{"default", 0, models.DefaultXPHistoryLimit},
+		{"negative", -1, models.DefaultXPHistoryLimit},
+		{"within bounds", 7, 7},
+		{"at the maximum", models.MaxXPHistoryLimit, models.MaxXPHistoryLimit},
+		{"above the maximum", models.MaxXPHistoryLimit + 1, models.MaxXPHistoryLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := f.svc.GetXPHistory(context.Background(), models.XPHistoryRequest{PointlingID: f.pointling, Limit: tt.limit})
+			require.NoError(t, err)
+			require.Len(t, res.Events, tt.want)
+			require.NotEmpty(t, res.NextCursor)
+			require.Equal(t, models.MaxXPHistoryLimit+5, res.Events[0].XPAmount, "pages start at the newest event")
+		})
+	}
+}
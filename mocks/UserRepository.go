@@ -1,124 +0,0 @@
-// Code generated by mockery v2.52.2. DO NOT EDIT.
-
-package mocks
-
-import (
-	models "my-pointlings-be/internal/models"
-
-	mock "github.com/stretchr/testify/mock"
-)
-
-// UserRepository is an autogenerated mock type for the UserRepository type
-type UserRepository struct {
-	mock.Mock
-}
-
-// CreateUser provides a mock function with given fields: user
-func (_m *UserRepository) CreateUser(user *models.User) error {
-	ret := _m.Called(user)
-
-	if len(ret) == 0 {
-		panic("no return value specified for CreateUser")
-	}
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(*models.User) error); ok {
-		r0 = rf(user)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
-// GetUser provides a mock function with given fields: userID
-func (_m *UserRepository) GetUser(userID int64) (*models.User, error) {
-	ret := _m.Called(userID)
-
-	if len(ret) == 0 {
-		panic("no return value specified for GetUser")
-	}
-
-	var r0 *models.User
-	var r1 error
-	if rf, ok := ret.Get(0).(func(int64) (*models.User, error)); ok {
-		return rf(userID)
-	}
-	if rf, ok := ret.Get(0).(func(int64) *models.User); ok {
-		r0 = rf(userID)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.User)
-		}
-	}
-
-	if rf, ok := ret.Get(1).(func(int64) error); ok {
-		r1 = rf(userID)
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
-}
-
-// ListUsers provides a mock function with given fields: limit, offset
-func (_m *UserRepository) ListUsers(limit int, offset int) ([]*models.User, error) {
-	ret := _m.Called(limit, offset)
-
-	if len(ret) == 0 {
-		panic("no return value specified for ListUsers")
-	}
-
-	var r0 []*models.User
-	var r1 error
-	if rf, ok := ret.Get(0).(func(int, int) ([]*models.User, error)); ok {
-		return rf(limit, offset)
-	}
-	if rf, ok := ret.Get(0).(func(int, int) []*models.User); ok {
-		r0 = rf(limit, offset)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*models.User)
-		}
-	}
-
-	if rf, ok := ret.Get(1).(func(int, int) error); ok {
-		r1 = rf(limit, offset)
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
-}
-
-// UpdatePointBalance provides a mock function with given fields: userID, newBalance
-func (_m *UserRepository) UpdatePointBalance(userID int64, newBalance int64) error {
-	ret := _m.Called(userID, newBalance)
-
-	if len(ret) == 0 {
-		panic("no return value specified for UpdatePointBalance")
-	}
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(int64, int64) error); ok {
-		r0 = rf(userID, newBalance)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
-// NewUserRepository creates a new instance of UserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
-// The first argument is typically a *testing.T value.
-func NewUserRepository(t interface {
-	mock.TestingT
-	Cleanup(func())
-}) *UserRepository {
-	mock := &UserRepository{}
-	mock.Mock.Test(t)
-
-	t.Cleanup(func() { mock.AssertExpectations(t) })
-
-	return mock
-}
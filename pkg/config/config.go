@@ -3,14 +3,59 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// Repository backends selectable with REPOSITORY.
+const (
+	RepositoryPostgres = "postgres"
+	RepositoryMemory   = "memory"
+)
+
 // Config holds runtime configuration loaded from environment.
 type Config struct {
 	DBAddr   string
 	HTTPAddr string
+
+	// Repository is the storage backend: postgres, or memory to run without
+	// a database. Memory data is lost on restart.
+	Repository string
+
+	// Connection pool tuning; zero leaves the database package defaults.
+	DBMaxOpenConns     int
+	DBMaxIdleConns     int
+	DBConnMaxLifetime  time.Duration
+	DBConnMaxIdleTime  time.Duration
+	DBStatementTimeout time.Duration
+	DBConnectTimeout   time.Duration
+
+	// AutoMigrate applies pending schema migrations on startup.
+	AutoMigrate bool
+
+	// RequestTimeout bounds how long a request, and the queries it runs,
+	// may take before it is cancelled.
+	RequestTimeout time.Duration
+
+	// IdempotencyTTL is how long an Idempotency-Key and its stored
+	// response are kept for replay.
+	IdempotencyTTL time.Duration
+
+	// JWT verification: a shared HS256 secret, a JWKS file of public keys,
+	// or both. Issuer and audience are checked when set; the user ID claim
+	// is required.
+	JWTSecret      string
+	JWKSFile       string
+	JWTIssuer      string
+	JWTAudience    string
+	JWTUserIDClaim string
+	JWTRolesClaim  string
+
+	// AdminAPIKeys is a comma-separated list of name:key pairs accepted by
+	// the /admin routes in place of an admin token.
+	AdminAPIKeys string
 }
 
 // Load reads .env (if present) and required variables from the environment.
@@ -20,11 +65,27 @@ func Load() *Config {
 	_ = godotenv.Load()
 
 	cfg := &Config{
-		DBAddr:   os.Getenv("SUPABASE_DB_URL"),
-		HTTPAddr: os.Getenv("HTTP_ADDR"),
+		DBAddr:         os.Getenv("SUPABASE_DB_URL"),
+		HTTPAddr:       os.Getenv("HTTP_ADDR"),
+		JWTSecret:      os.Getenv("SUPABASE_JWT_SECRET"),
+		JWKSFile:       os.Getenv("JWT_JWKS_FILE"),
+		JWTIssuer:      os.Getenv("JWT_ISSUER"),
+		JWTAudience:    os.Getenv("JWT_AUDIENCE"),
+		JWTUserIDClaim: os.Getenv("JWT_USER_ID_CLAIM"),
+		JWTRolesClaim:  os.Getenv("JWT_ROLES_CLAIM"),
+		AdminAPIKeys:   os.Getenv("ADMIN_API_KEYS"),
+		Repository:     os.Getenv("REPOSITORY"),
+	}
+
+	switch cfg.Repository {
+	case "":
+		cfg.Repository = RepositoryPostgres
+	case RepositoryPostgres, RepositoryMemory:
+	default:
+		log.Fatalf("invalid REPOSITORY %q: must be %s or %s", cfg.Repository, RepositoryPostgres, RepositoryMemory)
 	}
 
-	if cfg.DBAddr == "" {
+	if cfg.Repository == RepositoryPostgres && cfg.DBAddr == "" {
 		log.Fatal("missing required environment variables: SUPABASE_DB_URL")
 	}
 
@@ -32,5 +93,43 @@ func Load() *Config {
 		cfg.HTTPAddr = ":8080"
 	}
 
+	cfg.AutoMigrate = os.Getenv("AUTO_MIGRATE") == "true"
+	cfg.RequestTimeout = durationEnv("REQUEST_TIMEOUT", 10*time.Second)
+	cfg.IdempotencyTTL = durationEnv("IDEMPOTENCY_TTL", 24*time.Hour)
+
+	cfg.DBMaxOpenConns = intEnv("DB_MAX_OPEN_CONNS", 0)
+	cfg.DBMaxIdleConns = intEnv("DB_MAX_IDLE_CONNS", 0)
+	cfg.DBConnMaxLifetime = durationEnv("DB_CONN_MAX_LIFETIME", 0)
+	cfg.DBConnMaxIdleTime = durationEnv("DB_CONN_MAX_IDLE_TIME", 0)
+	cfg.DBStatementTimeout = durationEnv("DB_STATEMENT_TIMEOUT", 0)
+	cfg.DBConnectTimeout = durationEnv("DB_CONNECT_TIMEOUT", 0)
+
 	return cfg
 }
+
+// durationEnv reads a positive duration such as "30s" from key, or returns
+// fallback when it is unset.
+func durationEnv(key string, fallback time.Duration) time.Duration {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		log.Fatalf("invalid %s %q: must be a positive duration", key, raw)
+	}
+	return d
+}
+
+// intEnv reads a positive integer from key, or returns fallback when it is unset.
+func intEnv(key string, fallback int) int {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n <= 0 {
+		log.Fatalf("invalid %s %q: must be a positive integer", key, raw)
+	}
+	return n
+}